@@ -0,0 +1,142 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Distinct - получить различные значения поля field среди документов, подходящих под фильтр.
+// Дедупликация выполняется на стороне сервера.
+func (r *Racs) Distinct(field string, filterData map[string]interface{}) ([]interface{}, error) {
+	return r.DistinctContext(context.Background(), field, filterData)
+}
+
+func (r *Racs) DistinctContext(ctx context.Context, field string, filterData map[string]interface{}) ([]interface{}, error) {
+	ctx = withOperation(ctx, "Distinct")
+
+	if field == "" {
+		return nil, errors.New(`"field" is required`)
+	}
+	filter := r.liveFilter(filterData)
+	if filter == nil {
+		filter = make(map[string]interface{})
+	}
+
+	url := r.buildURL("distinct")
+	payload, err := r.marshal(map[string]interface{}{
+		"field":  field,
+		"filter": filter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.makeRequest(ctx, "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := resp["data"]
+	if !ok || value == nil {
+		return []interface{}{}, nil
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response shape: data is %T, not an array", value)
+	}
+
+	return values, nil
+}
+
+// Aggregate - выполнить конвейер агрегации в стиле MongoDB ($match, $group, $sort и т.д.).
+// Стадии передаются на сервер без изменений.
+func (r *Racs) Aggregate(pipeline []map[string]interface{}) ([]map[string]interface{}, error) {
+	return r.AggregateContext(context.Background(), pipeline)
+}
+
+func (r *Racs) AggregateContext(ctx context.Context, pipeline []map[string]interface{}) ([]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "Aggregate")
+
+	if len(pipeline) == 0 {
+		return nil, errors.New(`"pipeline" is required`)
+	}
+
+	url := r.buildURL("aggregate")
+	payload, err := r.marshal(map[string]interface{}{
+		"pipeline": pipeline,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.makeRequest(ctx, "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return responseDocuments(resp)
+}
+
+// PeekByFilter - получить общее число документов, подходящих под фильтр, и первые
+// previewLimit из них с учётом сортировки за один запрос (агрегация с $facet).
+// Для сортировки nil действуют те же значения по умолчанию, что и в ReadPostByFilter.
+func (r *Racs) PeekByFilter(filterData interface{}, sort interface{}, previewLimit int) (int64, []map[string]interface{}, error) {
+	return r.PeekByFilterContext(context.Background(), filterData, sort, previewLimit)
+}
+
+func (r *Racs) PeekByFilterContext(ctx context.Context, filterData interface{}, sort interface{}, previewLimit int) (int64, []map[string]interface{}, error) {
+	ctx = withOperation(ctx, "PeekByFilter")
+
+	if previewLimit <= 0 {
+		return 0, nil, errors.New(`"preview_limit" must be positive`)
+	}
+	filterData = r.liveFilter(filterData)
+	if filterData == nil {
+		filterData = make(map[string]interface{})
+	}
+	if sort == nil {
+		sort = r.defaultSort
+	}
+	if sort == nil {
+		sort = map[string]int{"_created": -1}
+	}
+
+	results, err := r.AggregateContext(ctx, []map[string]interface{}{
+		{"$match": filterData},
+		{"$facet": map[string]interface{}{
+			"count": []map[string]interface{}{
+				{"$count": "count"},
+			},
+			"sample": []map[string]interface{}{
+				{"$sort": sort},
+				{"$limit": previewLimit},
+			},
+		}},
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(results) == 0 {
+		return 0, []map[string]interface{}{}, nil
+	}
+
+	var count int64
+	if counts, ok := results[0]["count"].([]interface{}); ok && len(counts) > 0 {
+		counted, ok := counts[0].(map[string]interface{})
+		if !ok {
+			return 0, nil, fmt.Errorf("unexpected response shape: count is %T, not an object", counts[0])
+		}
+		if count, err = responseCount(counted, "count"); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	sample, err := responseDocuments(map[string]interface{}{"data": results[0]["sample"]})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return count, sample, nil
+}
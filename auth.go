@@ -0,0 +1,63 @@
+package racs
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Authenticator - добавляет учётные данные к запросу непосредственно перед отправкой.
+// Вызывается для каждой попытки, поэтому может возвращать периодически обновляемые данные.
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// AuthenticatorFunc - адаптер для использования функции в качестве Authenticator
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// WithAuthenticator - использовать произвольный Authenticator
+func WithAuthenticator(a Authenticator) Option {
+	return func(r *Racs) error {
+		if a == nil {
+			return errors.New("authenticator can't be nil")
+		}
+		r.auth = a
+		return nil
+	}
+}
+
+// WithAPIKey - передавать API-ключ в заголовке X-API-Key
+func WithAPIKey(key string) Option {
+	return func(r *Racs) error {
+		if key == "" {
+			return errors.New("api key can't be empty")
+		}
+		r.auth = staticHeader{key: "X-API-Key", value: key}
+		return nil
+	}
+}
+
+// WithBearerToken - передавать токен в заголовке Authorization: Bearer <token>
+func WithBearerToken(token string) Option {
+	return func(r *Racs) error {
+		if token == "" {
+			return errors.New("bearer token can't be empty")
+		}
+		r.auth = staticHeader{key: "Authorization", value: "Bearer " + token}
+		return nil
+	}
+}
+
+// staticHeader - Authenticator, устанавливающий фиксированный заголовок
+type staticHeader struct {
+	key   string
+	value string
+}
+
+func (h staticHeader) Apply(req *http.Request) error {
+	req.Header.Set(h.key, h.value)
+	return nil
+}
@@ -0,0 +1,215 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// defaultBatchSize - размер пакета для массовых операций по умолчанию
+const defaultBatchSize = 100
+
+// WithBatchSize - максимальное число документов в одном запросе массовых операций
+func WithBatchSize(n int) Option {
+	return func(r *Racs) error {
+		if n <= 0 {
+			return errors.New("batch size must be positive")
+		}
+		r.batchSize = n
+		return nil
+	}
+}
+
+// CreatePosts - создать несколько документов, отправляя их пакетами (см. WithBatchSize).
+// Возвращает ID созданных документов в порядке входных данных. При ошибке в очередном
+// пакете возвращаются ID уже созданных документов вместе с ошибкой.
+func (r *Racs) CreatePosts(data []map[string]interface{}) ([]string, error) {
+	return r.CreatePostsContext(context.Background(), data)
+}
+
+func (r *Racs) CreatePostsContext(ctx context.Context, data []map[string]interface{}) ([]string, error) {
+	ctx = withOperation(ctx, "CreatePosts")
+
+	if len(data) == 0 {
+		return nil, errors.New(`"data" is required`)
+	}
+	for i, doc := range data {
+		if doc == nil {
+			return nil, fmt.Errorf(`"data[%d]" is required`, i)
+		}
+		if err := r.checkRequired(doc); err != nil {
+			return nil, fmt.Errorf(`"data[%d]": %w`, i, err)
+		}
+	}
+
+	batchSize := r.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	url := r.buildURL("bulk")
+	ids := make([]string, 0, len(data))
+	for start := 0; start < len(data); start += batchSize {
+		end := min(start+batchSize, len(data))
+		batch := data[start:end]
+
+		payload, err := r.marshal(batch)
+		if err != nil {
+			return ids, err
+		}
+
+		resp, err := r.makeRequest(ctx, "POST", url, bytes.NewBuffer(payload))
+		if err != nil {
+			return ids, err
+		}
+
+		batchIDs, err := insertedIDs(resp, len(batch))
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, batchIDs...)
+	}
+
+	return ids, nil
+}
+
+// insertedIDs - достаёт ID созданных документов из поля "insertedIds".
+// Сервер может вернуть как массив, так и объект вида {"0": id, "1": id}.
+func insertedIDs(resp map[string]interface{}, count int) ([]string, error) {
+	ids := make([]string, count)
+
+	switch value := resp["insertedIds"].(type) {
+	case []interface{}:
+		if len(value) != count {
+			return nil, fmt.Errorf("unexpected response shape: got %d insertedIds for %d documents", len(value), count)
+		}
+		for i, id := range value {
+			ids[i] = idString(id)
+		}
+	case map[string]interface{}:
+		for key, id := range value {
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= count {
+				return nil, fmt.Errorf("unexpected response shape: invalid insertedIds key %q", key)
+			}
+			ids[i] = idString(id)
+		}
+	default:
+		return nil, errors.New("unexpected response shape: missing insertedIds")
+	}
+
+	return ids, nil
+}
+
+// DeletePostsByIDs - удалить документы с указанными ID одним запросом.
+// Возвращает общее число удалённых документов или -1, если сервер ответил пустым телом
+// (например, 204 No Content) и число неизвестно.
+func (r *Racs) DeletePostsByIDs(ids []string) (int64, error) {
+	return r.DeletePostsByIDsContext(context.Background(), ids)
+}
+
+func (r *Racs) DeletePostsByIDsContext(ctx context.Context, ids []string) (int64, error) {
+	ctx = withOperation(ctx, "DeletePostsByIDs")
+
+	if len(ids) == 0 {
+		return 0, errors.New(`"ids" is required`)
+	}
+
+	resp, err := r.DeletePostByFilterContext(ctx, r.byIDs(ids))
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := newDeleteResult(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// ReadPostsByIDs - прочитать документы с указанными ID одним запросом с фильтром $in.
+// Результат индексирован по ID; отсутствующие ID в него не попадают.
+func (r *Racs) ReadPostsByIDs(ids []string) (map[string]map[string]interface{}, error) {
+	return r.ReadPostsByIDsContext(context.Background(), ids)
+}
+
+func (r *Racs) ReadPostsByIDsContext(ctx context.Context, ids []string) (map[string]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostsByIDs")
+
+	if len(ids) == 0 {
+		return nil, errors.New(`"ids" is required`)
+	}
+
+	resp, err := r.readByFilter(ctx, readQuery{
+		filter: r.byIDs(ids),
+		limit:  len(ids),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := responseDocuments(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]map[string]interface{}, len(docs))
+	for _, doc := range docs {
+		if id := idString(doc["_id"]); id != "" {
+			result[id] = doc
+		}
+	}
+
+	return result, nil
+}
+
+// ReadPostsByIDsParallel - вариант ReadPostsByIDs для серверов без поддержки $in:
+// выполняет ReadPostByID параллельно не более чем в workers горутинах.
+// Отсутствующие ID в результат не попадают; при иной ошибке возвращается первая из них.
+func (r *Racs) ReadPostsByIDsParallel(ids []string, workers int) (map[string]map[string]interface{}, error) {
+	return r.ReadPostsByIDsParallelContext(context.Background(), ids, workers)
+}
+
+func (r *Racs) ReadPostsByIDsParallelContext(ctx context.Context, ids []string, workers int) (map[string]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostsByIDsParallel")
+
+	if len(ids) == 0 {
+		return nil, errors.New(`"ids" is required`)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		result   = make(map[string]map[string]interface{}, len(ids))
+		firstErr error
+	)
+	forEachParallel(ctx, len(ids), workers, func(ctx context.Context, i int) {
+		doc, err := r.ReadPostByIDContext(ctx, ids[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			result[ids[i]] = doc
+		case errors.Is(err, ErrNotFound):
+		case firstErr == nil:
+			firstErr = err
+			cancel()
+		}
+	})
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
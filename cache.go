@@ -0,0 +1,163 @@
+package racs
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithReadCache - кэшировать в памяти до size ответов ReadPostByID (и ReadPostByIDInto) на время ttl.
+// Запись удаляется при обновлении, замене или удалении документа по ID; обновления и удаления
+// по фильтру, а также Do с методом, отличным от GET и HEAD, очищают кэш текущих resource и dataset.
+// Кэш общий для копий из Clone, записи разных resource и dataset не пересекаются.
+// Изменения, сделанные другими клиентами, становятся видны не позднее чем через ttl.
+// Отдельный вызов может обойти кэш через контекст NoCache.
+func WithReadCache(size int, ttl time.Duration) Option {
+	return func(r *Racs) error {
+		if size <= 0 {
+			return errors.New("read cache size must be positive")
+		}
+		if ttl <= 0 {
+			return errors.New("read cache ttl must be positive")
+		}
+		r.cache = newReadCache(size, ttl)
+		return nil
+	}
+}
+
+// noCacheKey - ключ контекста для NoCache
+type noCacheKey struct{}
+
+// NoCache - возвращает контекст, с которым чтение по ID выполняется запросом к серверу
+// в обход WithReadCache; полученный ответ сохраняется в кэш
+func NoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// cachePrefix - префикс ключей кэша для resource и dataset экземпляра
+func (r *Racs) cachePrefix() string {
+	return r.Resource + "\x00" + r.Dataset + "\x00"
+}
+
+// cachedPost - тело ответа из кэша или nil
+func (r *Racs) cachedPost(ctx context.Context, postID string) []byte {
+	if r.cache == nil {
+		return nil
+	}
+	if bypass, _ := ctx.Value(noCacheKey{}).(bool); bypass {
+		return nil
+	}
+	return r.cache.get(r.cachePrefix() + postID)
+}
+
+// cachePost - сохраняет тело ответа на чтение по ID
+func (r *Racs) cachePost(postID string, data []byte) {
+	if r.cache != nil {
+		r.cache.put(r.cachePrefix()+postID, data)
+	}
+}
+
+// invalidatePost - удаляет документ из кэша после его изменения
+func (r *Racs) invalidatePost(postID string) {
+	if r.cache != nil {
+		r.cache.remove(r.cachePrefix() + postID)
+	}
+}
+
+// invalidateDataset - очищает кэш текущих resource и dataset после изменений по фильтру
+func (r *Racs) invalidateDataset() {
+	if r.cache != nil {
+		r.cache.removePrefix(r.cachePrefix())
+	}
+}
+
+// readCache - потокобезопасный LRU-кэш тел ответов с ограничением времени жизни
+type readCache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+func newReadCache(size int, ttl time.Duration) *readCache {
+	return &readCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get - копия закэшированного тела, чтобы изменения вызывающего не портили кэш
+func (c *readCache) get(key string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return bytes.Clone(entry.data)
+}
+
+func (c *readCache) put(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data = bytes.Clone(data)
+	expires := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.data, entry.expires = data, expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, data: data, expires: expires})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *readCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+func (c *readCache) removePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.order.Remove(elem)
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen - запрос не отправлен, потому что автоматический выключатель разомкнут
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState - состояние автоматического выключателя
+type CircuitState int
+
+const (
+	// CircuitClosed - запросы отправляются как обычно
+	CircuitClosed CircuitState = iota
+	// CircuitOpen - запросы сразу завершаются ErrCircuitOpen до окончания паузы
+	CircuitOpen
+	// CircuitHalfOpen - пауза истекла, отправляется пробный запрос
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// WithCircuitBreaker - после threshold сбоев подряд (сетевые ошибки и ответы 5xx) перестать
+// обращаться к серверу на время cooldown: методы сразу возвращают ошибку, оборачивающую
+// ErrCircuitOpen. По истечении паузы пропускается один пробный запрос; при успехе выключатель
+// замыкается, при сбое снова размыкается. Выключатель общий для копий из Clone.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(r *Racs) error {
+		if threshold <= 0 {
+			return errors.New("circuit breaker threshold must be positive")
+		}
+		if cooldown <= 0 {
+			return errors.New("circuit breaker cooldown must be positive")
+		}
+		r.breaker = &circuitBreaker{threshold: threshold, cooldown: cooldown}
+		return nil
+	}
+}
+
+// CircuitState - текущее состояние выключателя из WithCircuitBreaker (без него всегда CircuitClosed)
+func (r *Racs) CircuitState() CircuitState {
+	if r.breaker == nil {
+		return CircuitClosed
+	}
+	return r.breaker.state()
+}
+
+// circuitBreaker - счётчик последовательных сбоев с паузой после размыкания
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *circuitBreaker) state() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.failures < b.threshold:
+		return CircuitClosed
+	case b.probing || time.Since(b.openedAt) >= b.cooldown:
+		return CircuitHalfOpen
+	}
+	return CircuitOpen
+}
+
+// allow - можно ли отправить запрос; в полуоткрытом состоянии пропускается один пробный запрос
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// circuitOutcome - как результат запроса влияет на выключатель
+type circuitOutcome int
+
+const (
+	circuitSuccess circuitOutcome = iota
+	circuitFailure
+	// circuitIgnored - результат ничего не говорит о сервере (например, запрос отменён вызывающим)
+	circuitIgnored
+)
+
+// record - учитывает результат запроса, пропущенного allow. Игнорируемый результат
+// только освобождает место пробного запроса, не меняя счётчик сбоев.
+func (b *circuitBreaker) record(outcome circuitOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	switch outcome {
+	case circuitSuccess:
+		b.failures = 0
+	case circuitFailure:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = time.Now()
+		}
+	}
+}
+
+// circuitResult - классифицирует результат запроса; отмена запроса вызывающим не учитывается
+func circuitResult(req *http.Request, res *http.Response, err error) circuitOutcome {
+	switch {
+	case err != nil && errors.Is(err, context.Canceled) && req.Context().Err() != nil:
+		return circuitIgnored
+	case err != nil, res.StatusCode >= 500:
+		return circuitFailure
+	}
+	return circuitSuccess
+}
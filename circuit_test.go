@@ -0,0 +1,145 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := &circuitBreaker{threshold: 2, cooldown: time.Hour}
+
+	b.record(circuitFailure)
+	if got := b.state(); got != CircuitClosed {
+		t.Fatalf("state after 1 failure = %v, want closed", got)
+	}
+	b.record(circuitFailure)
+	if got := b.state(); got != CircuitOpen {
+		t.Fatalf("state after 2 failures = %v, want open", got)
+	}
+	if b.allow() {
+		t.Fatal("allow() = true while open")
+	}
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	b := &circuitBreaker{threshold: 2, cooldown: time.Hour}
+
+	b.record(circuitFailure)
+	b.record(circuitSuccess)
+	b.record(circuitFailure)
+	if got := b.state(); got != CircuitClosed {
+		t.Fatalf("state = %v, want closed", got)
+	}
+}
+
+func TestCircuitBreakerIgnoredKeepsFailures(t *testing.T) {
+	b := &circuitBreaker{threshold: 2, cooldown: time.Hour}
+
+	b.record(circuitFailure)
+	b.record(circuitIgnored)
+	b.record(circuitFailure)
+	if got := b.state(); got != CircuitOpen {
+		t.Fatalf("state = %v, want open", got)
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	b := &circuitBreaker{threshold: 1, cooldown: 10 * time.Millisecond}
+	b.record(circuitFailure)
+	time.Sleep(20 * time.Millisecond)
+
+	if got := b.state(); got != CircuitHalfOpen {
+		t.Fatalf("state after cooldown = %v, want half-open", got)
+	}
+	if !b.allow() {
+		t.Fatal("allow() = false for the probe")
+	}
+	if b.allow() {
+		t.Fatal("allow() = true for a second request while probing")
+	}
+
+	b.record(circuitSuccess)
+	if got := b.state(); got != CircuitClosed {
+		t.Fatalf("state after successful probe = %v, want closed", got)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	b := &circuitBreaker{threshold: 1, cooldown: 10 * time.Millisecond}
+	b.record(circuitFailure)
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("allow() = false for the probe")
+	}
+	b.record(circuitFailure)
+	if got := b.state(); got != CircuitOpen {
+		t.Fatalf("state after failed probe = %v, want open", got)
+	}
+}
+
+func TestCircuitBreakerIgnoredProbe(t *testing.T) {
+	b := &circuitBreaker{threshold: 1, cooldown: 10 * time.Millisecond}
+	b.record(circuitFailure)
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("allow() = false for the probe")
+	}
+	b.record(circuitIgnored)
+	if got := b.state(); got != CircuitHalfOpen {
+		t.Fatalf("state after ignored probe = %v, want half-open", got)
+	}
+	if !b.allow() {
+		t.Fatal("allow() = false for the next probe")
+	}
+}
+
+func TestCircuitBreakerCancelledRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("X-Slow") != "" {
+			<-req.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	r, err := NewRacs("resource", "dataset",
+		WithBaseURL(srv.URL),
+		WithCircuitBreaker(2, time.Hour),
+		WithHeaderFromContext("X-Slow", func(ctx context.Context) string {
+			slow, _ := ctx.Value(slowKey{}).(string)
+			return slow
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.ReadPostByID("x"); err == nil {
+		t.Fatal("ReadPostByID() error = nil, want 500")
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), slowKey{}, "1"))
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := r.ReadPostByIDContext(ctx, "x"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ReadPostByIDContext() error = %v, want context.Canceled", err)
+	}
+	if got := r.CircuitState(); got != CircuitClosed {
+		t.Fatalf("state after cancel = %v, want closed", got)
+	}
+
+	if _, err := r.ReadPostByID("x"); err == nil {
+		t.Fatal("ReadPostByID() error = nil, want 500")
+	}
+	if got := r.CircuitState(); got != CircuitOpen {
+		t.Fatalf("state = %v, want open: cancellation must not reset failures", got)
+	}
+}
+
+type slowKey struct{}
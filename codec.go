@@ -0,0 +1,34 @@
+package racs
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// JSONCodec - кодирование тел запросов и декодирование ответов.
+// По умолчанию используется encoding/json; совместимые реализации (jsoniter,
+// go-json и т.п.) подключаются через WithJSONCodec.
+type JSONCodec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// WithJSONCodec - использовать собственный JSONCodec вместо encoding/json.
+// WithUseNumber при этом не действует: декодирование чисел определяет сам кодек.
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(r *Racs) error {
+		if codec == nil {
+			return errors.New("json codec can't be nil")
+		}
+		r.codec = codec
+		return nil
+	}
+}
+
+// marshal - кодирует v в JSON кодеком экземпляра
+func (r *Racs) marshal(v interface{}) ([]byte, error) {
+	if r.codec != nil {
+		return r.codec.Marshal(v)
+	}
+	return json.Marshal(v)
+}
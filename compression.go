@@ -0,0 +1,66 @@
+package racs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithCompression - сжимать тела JSON-запросов gzip (Content-Encoding: gzip) и
+// запрашивать сжатые ответы (Accept-Encoding: gzip), распаковывая их прозрачно.
+// Тела multipart-загрузок файлов не сжимаются.
+func WithCompression() Option {
+	return func(r *Racs) error {
+		r.compression = true
+		return nil
+	}
+}
+
+// compressBody - возвращает gzip-сжатую копию тела запроса
+func compressBody(body io.Reader) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(zw, body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// decompressResponse - подменяет тело gzip-ответа распаковывающим reader'ом
+func decompressResponse(res *http.Response) {
+	if !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+
+	res.Body = &gzipBody{body: res.Body}
+	res.Header.Del("Content-Encoding")
+	res.Header.Del("Content-Length")
+	res.ContentLength = -1
+	res.Uncompressed = true
+}
+
+// gzipBody - лениво создаёт gzip.Reader при первом чтении, чтобы пустые тела не приводили к ошибке
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.zr == nil && g.err == nil {
+		g.zr, g.err = gzip.NewReader(g.body)
+	}
+	if g.err != nil {
+		return 0, g.err
+	}
+	return g.zr.Read(p)
+}
+
+func (g *gzipBody) Close() error {
+	return g.body.Close()
+}
@@ -0,0 +1,132 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVersionConflict - документ существует, но его версия не совпала с ожидаемой.
+// errors.Is(err, ErrNoUpdatesMade) для неё также истинно.
+var ErrVersionConflict = fmt.Errorf("version conflict: %w", ErrNoUpdatesMade)
+
+// UpdatePostByIDIfVersion - обновить документ, только если поле versionField равно expected
+// (оптимистичная блокировка). Увеличивать версию должен сам updateOptions, например
+// {"$set": {...}, "$inc": {"version": 1}}. Если документ не найден, ошибка оборачивает
+// ErrNotFound; если версия изменилась - возвращается ErrVersionConflict.
+func (r *Racs) UpdatePostByIDIfVersion(postID, versionField string, expected interface{}, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	return r.UpdatePostByIDIfVersionContext(context.Background(), postID, versionField, expected, updateOptions)
+}
+
+func (r *Racs) UpdatePostByIDIfVersionContext(ctx context.Context, postID, versionField string, expected interface{}, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpdatePostByIDIfVersion")
+
+	if postID == "" {
+		return nil, errors.New(`"post_id" is required`)
+	}
+	if versionField == "" {
+		return nil, errors.New(`"version_field" is required`)
+	}
+	if updateOptions == nil {
+		return nil, errors.New(`"update_options" is required`)
+	}
+
+	update, err := setUpdate(updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := r.ByID(postID)
+	filter[versionField] = expected
+
+	resp, err := r.updateByFilter(ctx, filter, update, false)
+	if !errors.Is(err, ErrNoUpdatesMade) {
+		return resp, err
+	}
+
+	exists, err := r.ExistsContext(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %w", ErrNoUpdatesMade, ErrNotFound)
+	}
+	return nil, ErrVersionConflict
+}
+
+// CreateIfNotExists - создать документ из data, только если ни один документ не подходит под фильтр.
+// Выполняется атомарно одним upsert с $setOnInsert, поэтому параллельные вызовы не создают дубликатов
+// (при наличии уникального индекса по полям фильтра). created сообщает, был ли документ создан;
+// doc - созданный документ с полем "_id" или найденный существующий (читается отдельным запросом).
+func (r *Racs) CreateIfNotExists(filterData, data map[string]interface{}) (bool, map[string]interface{}, error) {
+	return r.CreateIfNotExistsContext(context.Background(), filterData, data)
+}
+
+func (r *Racs) CreateIfNotExistsContext(ctx context.Context, filterData, data map[string]interface{}) (bool, map[string]interface{}, error) {
+	ctx = withOperation(ctx, "CreateIfNotExists")
+
+	if filterData == nil {
+		return false, nil, errors.New(`"filter_data" is required`)
+	}
+	if data == nil {
+		return false, nil, errors.New(`"data" is required`)
+	}
+	if err := r.checkRequired(data); err != nil {
+		return false, nil, err
+	}
+	if err := Validate(data); err != nil {
+		return false, nil, err
+	}
+
+	url := r.buildURL()
+	payload, err := r.marshal(map[string]interface{}{
+		"filter": filterData,
+		"update": map[string]interface{}{"$setOnInsert": data},
+		"upsert": true,
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	resp, err := r.makeRequest(ctx, "PATCH", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return false, nil, err
+	}
+
+	if id := resp["upsertedId"]; id != nil {
+		doc := make(map[string]interface{}, len(filterData)+len(data)+1)
+		// как и сервер при upsert, переносим из фильтра только условия на равенство
+		for key, value := range filterData {
+			if !strings.HasPrefix(key, "$") && !isOperatorDocument(value) {
+				doc[key] = value
+			}
+		}
+		for key, value := range data {
+			doc[key] = value
+		}
+		doc["_id"] = id
+		return true, doc, nil
+	}
+
+	doc, err := r.FindOneContext(ctx, filterData, nil)
+	if err != nil {
+		return false, nil, err
+	}
+	return false, doc, nil
+}
+
+// isOperatorDocument - true для условий вида {"$gt": 1}
+func isOperatorDocument(value interface{}) bool {
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key := range doc {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
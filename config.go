@@ -0,0 +1,178 @@
+package racs
+
+import (
+	"errors"
+	"time"
+)
+
+// defaultConfigRetryDelay - базовая задержка повторов, если Config.RetryBaseDelay не задан
+const defaultConfigRetryDelay = 500 * time.Millisecond
+
+// Config - настройки для NewRacsFromConfig, удобные для загрузки из YAML или JSON.
+// Нулевое значение поля означает значение по умолчанию; поля соответствуют одноимённым опциям.
+// Поля time.Duration в JSON задаются целым числом наносекунд: encoding/json не разбирает
+// строки вида "5s" (gopkg.in/yaml.v3 их разбирает).
+type Config struct {
+	Resource string `json:"resource" yaml:"resource"`
+	Dataset  string `json:"dataset" yaml:"dataset"`
+	// BaseURL - см. WithBaseURL (по умолчанию DefaultBaseURL)
+	BaseURL string `json:"baseURL" yaml:"baseURL"`
+
+	// Timeout - таймаут каждой попытки (WithTimeout)
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	// DefaultTimeout - дедлайн вызова без дедлайна в контексте (WithDefaultTimeout)
+	DefaultTimeout time.Duration `json:"defaultTimeout" yaml:"defaultTimeout"`
+
+	// APIKey и BearerToken - способ аутентификации; можно задать только один из них
+	APIKey      string `json:"apiKey" yaml:"apiKey"`
+	BearerToken string `json:"bearerToken" yaml:"bearerToken"`
+
+	UserAgent   string            `json:"userAgent" yaml:"userAgent"`
+	Headers     map[string]string `json:"headers" yaml:"headers"`
+	QueryParams map[string]string `json:"queryParams" yaml:"queryParams"`
+
+	// MaxRetries - число повторов после первой попытки (WithRetry с MaxRetries+1 попытками)
+	MaxRetries int `json:"maxRetries" yaml:"maxRetries"`
+	// RetryBaseDelay - базовая задержка повторов (по умолчанию 500 мс)
+	RetryBaseDelay time.Duration `json:"retryBaseDelay" yaml:"retryBaseDelay"`
+	RetryOnDelete  bool          `json:"retryOnDelete" yaml:"retryOnDelete"`
+
+	// RateLimit - запросов в секунду (WithRateLimit); RateBurst по умолчанию 1
+	RateLimit float64 `json:"rateLimit" yaml:"rateLimit"`
+	RateBurst int     `json:"rateBurst" yaml:"rateBurst"`
+
+	// CircuitBreakerThreshold и CircuitBreakerCooldown - см. WithCircuitBreaker; задаются вместе
+	CircuitBreakerThreshold int           `json:"circuitBreakerThreshold" yaml:"circuitBreakerThreshold"`
+	CircuitBreakerCooldown  time.Duration `json:"circuitBreakerCooldown" yaml:"circuitBreakerCooldown"`
+
+	MaxIdleConns        int           `json:"maxIdleConns" yaml:"maxIdleConns"`
+	MaxIdleConnsPerHost int           `json:"maxIdleConnsPerHost" yaml:"maxIdleConnsPerHost"`
+	IdleConnTimeout     time.Duration `json:"idleConnTimeout" yaml:"idleConnTimeout"`
+	// InsecureSkipVerify - только для локальной разработки, см. WithInsecureSkipVerify
+	InsecureSkipVerify bool `json:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+
+	MaxResponseSize  int64    `json:"maxResponseSize" yaml:"maxResponseSize"`
+	Compression      bool     `json:"compression" yaml:"compression"`
+	UseNumber        bool     `json:"useNumber" yaml:"useNumber"`
+	BatchSize        int      `json:"batchSize" yaml:"batchSize"`
+	DefaultLimit     int      `json:"defaultLimit" yaml:"defaultLimit"`
+	RequiredFields   []string `json:"requiredFields" yaml:"requiredFields"`
+	SoftDeleteFilter bool     `json:"softDeleteFilter" yaml:"softDeleteFilter"`
+	IdempotencyKeys  bool     `json:"idempotencyKeys" yaml:"idempotencyKeys"`
+	SilentWarnings   bool     `json:"silentWarnings" yaml:"silentWarnings"`
+}
+
+// NewRacsFromConfig - конструктор из Config. Дополнительные opts применяются после настроек
+// из cfg, например для хуков и клиентов, которые нельзя описать в файле конфигурации.
+func NewRacsFromConfig(cfg Config, opts ...Option) (*Racs, error) {
+	configOpts, err := cfg.options()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRacs(cfg.Resource, cfg.Dataset, append(configOpts, opts...)...)
+}
+
+// options - переводит Config в функциональные опции
+func (cfg Config) options() ([]Option, error) {
+	var opts []Option
+
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.Timeout != 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	if cfg.DefaultTimeout != 0 {
+		opts = append(opts, WithDefaultTimeout(cfg.DefaultTimeout))
+	}
+
+	switch {
+	case cfg.APIKey != "" && cfg.BearerToken != "":
+		return nil, errors.New("only one of api key and bearer token can be set")
+	case cfg.APIKey != "":
+		opts = append(opts, WithAPIKey(cfg.APIKey))
+	case cfg.BearerToken != "":
+		opts = append(opts, WithBearerToken(cfg.BearerToken))
+	}
+
+	if cfg.UserAgent != "" {
+		opts = append(opts, WithUserAgent(cfg.UserAgent))
+	}
+	for key, value := range cfg.Headers {
+		opts = append(opts, WithHeader(key, value))
+	}
+	for key, value := range cfg.QueryParams {
+		opts = append(opts, WithQueryParam(key, value))
+	}
+
+	if cfg.MaxRetries < 0 {
+		return nil, errors.New("max retries can't be negative")
+	}
+	if cfg.MaxRetries == 0 && (cfg.RetryBaseDelay != 0 || cfg.RetryOnDelete) {
+		return nil, errors.New("retry base delay and retry on delete require max retries")
+	}
+	if cfg.MaxRetries > 0 {
+		delay := cfg.RetryBaseDelay
+		if delay == 0 {
+			delay = defaultConfigRetryDelay
+		}
+		opts = append(opts, WithRetry(cfg.MaxRetries+1, delay))
+	}
+	if cfg.RetryOnDelete {
+		opts = append(opts, WithRetryOnDelete())
+	}
+
+	if cfg.RateLimit == 0 && cfg.RateBurst != 0 {
+		return nil, errors.New("rate burst requires rate limit")
+	}
+	if cfg.RateLimit != 0 {
+		opts = append(opts, WithRateLimit(cfg.RateLimit, max(cfg.RateBurst, 1)))
+	}
+	if cfg.CircuitBreakerThreshold != 0 || cfg.CircuitBreakerCooldown != 0 {
+		opts = append(opts, WithCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown))
+	}
+
+	if cfg.MaxIdleConns != 0 {
+		opts = append(opts, WithMaxIdleConns(cfg.MaxIdleConns))
+	}
+	if cfg.MaxIdleConnsPerHost != 0 {
+		opts = append(opts, WithMaxIdleConnsPerHost(cfg.MaxIdleConnsPerHost))
+	}
+	if cfg.IdleConnTimeout != 0 {
+		opts = append(opts, WithIdleConnTimeout(cfg.IdleConnTimeout))
+	}
+	if cfg.InsecureSkipVerify {
+		opts = append(opts, WithInsecureSkipVerify())
+	}
+
+	if cfg.MaxResponseSize != 0 {
+		opts = append(opts, WithMaxResponseSize(cfg.MaxResponseSize))
+	}
+	if cfg.Compression {
+		opts = append(opts, WithCompression())
+	}
+	if cfg.UseNumber {
+		opts = append(opts, WithUseNumber())
+	}
+	if cfg.BatchSize != 0 {
+		opts = append(opts, WithBatchSize(cfg.BatchSize))
+	}
+	if cfg.DefaultLimit != 0 {
+		opts = append(opts, WithDefaultLimit(cfg.DefaultLimit))
+	}
+	if len(cfg.RequiredFields) > 0 {
+		opts = append(opts, WithRequiredFields(cfg.RequiredFields...))
+	}
+	if cfg.SoftDeleteFilter {
+		opts = append(opts, WithSoftDeleteFilter())
+	}
+	if cfg.IdempotencyKeys {
+		opts = append(opts, WithIdempotencyKeys())
+	}
+	if cfg.SilentWarnings {
+		opts = append(opts, WithSilentWarnings())
+	}
+
+	return opts, nil
+}
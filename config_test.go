@@ -0,0 +1,48 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConfigTimeoutWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-req.Context().Done():
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	r, err := NewRacsFromConfig(Config{
+		Resource: "resource",
+		Dataset:  "dataset",
+		BaseURL:  srv.URL,
+		Timeout:  50 * time.Millisecond,
+	}, WithHTTPClient(&http.Client{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.ReadPostByID("x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ReadPostByID() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestConfigRejectsIgnoredFields(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"rate burst":      {RateBurst: 5},
+		"retry delay":     {RetryBaseDelay: time.Second},
+		"retry on delete": {RetryOnDelete: true},
+	} {
+		cfg.Resource, cfg.Dataset = "resource", "dataset"
+		if _, err := NewRacsFromConfig(cfg); err == nil {
+			t.Errorf("%s: NewRacsFromConfig() error = nil", name)
+		}
+	}
+}
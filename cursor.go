@@ -0,0 +1,124 @@
+package racs
+
+import (
+	"context"
+	"fmt"
+)
+
+// Cursor - курсор по результату чтения по фильтру в стиле драйвера MongoDB.
+// Документы загружаются лениво страницами по pageSize через Paginator.
+//
+//	cur, err := r.Find(filter, racs.Sort().Asc("_created"), 100)
+//	for cur.Next() {
+//		doc := cur.Doc()
+//	}
+//	if err := cur.Err(); err != nil {
+//		...
+//	}
+type Cursor struct {
+	racs   *Racs
+	filter interface{}
+	pages  *Paginator
+
+	page    []map[string]interface{}
+	current map[string]interface{}
+	err     error
+}
+
+// Find - создать курсор по документам, подходящим под фильтр
+func (r *Racs) Find(filterData interface{}, sort interface{}, pageSize int) (*Cursor, error) {
+	pages, err := r.Paginate(filterData, sort, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor{racs: r, filter: filterData, pages: pages}, nil
+}
+
+// Next - перейти к следующему документу, при необходимости загрузив следующую страницу.
+// Возвращает false, когда документы закончились или произошла ошибка (см. Err).
+func (c *Cursor) Next() bool {
+	return c.NextContext(context.Background())
+}
+
+func (c *Cursor) NextContext(ctx context.Context) bool {
+	ctx = withOperation(ctx, "Cursor.Next")
+
+	if c.err != nil {
+		return false
+	}
+
+	for len(c.page) == 0 {
+		if c.pages.Done() {
+			c.current = nil
+			return false
+		}
+		if c.page, c.err = c.pages.NextContext(ctx); c.err != nil {
+			c.current = nil
+			return false
+		}
+	}
+
+	c.current, c.page = c.page[0], c.page[1:]
+	return true
+}
+
+// Doc - текущий документ после успешного Next
+func (c *Cursor) Doc() map[string]interface{} {
+	return c.current
+}
+
+// Err - ошибка, остановившая обход
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// All - прочитать все оставшиеся документы
+func (c *Cursor) All() ([]map[string]interface{}, error) {
+	return c.AllContext(context.Background())
+}
+
+func (c *Cursor) AllContext(ctx context.Context) ([]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "Cursor.All")
+
+	docs := []map[string]interface{}{}
+	for c.NextContext(ctx) {
+		docs = append(docs, c.current)
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return docs, nil
+}
+
+// Count - общее число документов, подходящих под фильтр курсора, независимо от позиции обхода.
+// Выполняет отдельный запрос агрегации ($match и $count).
+func (c *Cursor) Count() (int64, error) {
+	return c.CountContext(context.Background())
+}
+
+func (c *Cursor) CountContext(ctx context.Context) (int64, error) {
+	ctx = withOperation(ctx, "Cursor.Count")
+
+	filter := c.racs.liveFilter(c.filter)
+	if filter == nil {
+		filter = make(map[string]interface{})
+	}
+
+	results, err := c.racs.AggregateContext(ctx, []map[string]interface{}{
+		{"$match": filter},
+		{"$count": "count"},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(results) {
+	case 0:
+		return 0, nil
+	case 1:
+		return responseCount(results[0], "count")
+	}
+	return 0, fmt.Errorf("unexpected response shape: got %d count results", len(results))
+}
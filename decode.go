@@ -0,0 +1,124 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+)
+
+// ReadPostByIDInto - прочитать документ по ID и декодировать его в значение типа T
+func ReadPostByIDInto[T any](r *Racs, postID string) (T, error) {
+	return ReadPostByIDIntoContext[T](context.Background(), r, postID)
+}
+
+func ReadPostByIDIntoContext[T any](ctx context.Context, r *Racs, postID string) (T, error) {
+	ctx = withOperation(ctx, "ReadPostByIDInto")
+
+	var result T
+	if postID == "" {
+		return result, errors.New(`"post_id" is required`)
+	}
+
+	url := r.buildURL(postID)
+	data, err := r.fetchPostByID(ctx, postID)
+	if err != nil {
+		return result, err
+	}
+
+	if isEmptyDocument(data) {
+		return result, ErrNotFound
+	}
+	if r.softDeleteFilter {
+		if doc, err := r.decodeMap(data); err == nil && r.softDeleted(doc) {
+			return result, ErrNotFound
+		}
+	}
+
+	if err := r.unmarshal(data, &result); err != nil {
+		return result, opError(ctx, url, err)
+	}
+
+	return result, nil
+}
+
+// CreatePostInto - создать документ и декодировать созданный документ в значение типа T.
+// Если сервер возвращает сам документ, декодируется он; если только {"insertedId": ...},
+// декодируются переданные данные с полем "_id", равным insertedId.
+func CreatePostInto[T any](r *Racs, data map[string]interface{}) (T, error) {
+	return CreatePostIntoContext[T](context.Background(), r, data)
+}
+
+func CreatePostIntoContext[T any](ctx context.Context, r *Racs, data map[string]interface{}) (T, error) {
+	ctx = withOperation(ctx, "CreatePostInto")
+
+	var result T
+	payload, err := r.createPayload(data)
+	if err != nil {
+		return result, err
+	}
+
+	url := r.buildURL()
+	raw, err := r.makeRawRequest(r.withIdempotencyKey(ctx), "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return result, err
+	}
+
+	resp, err := r.decodeMap(raw)
+	if err != nil {
+		return result, opError(ctx, url, err)
+	}
+
+	if id, ok := resp["insertedId"]; ok && resp["_id"] == nil {
+		doc := make(map[string]interface{}, len(data)+1)
+		for key, value := range data {
+			doc[key] = value
+		}
+		doc["_id"] = id
+
+		if raw, err = r.marshal(doc); err != nil {
+			return result, err
+		}
+	}
+
+	if err := r.unmarshal(raw, &result); err != nil {
+		return result, opError(ctx, url, err)
+	}
+
+	return result, nil
+}
+
+// FindOneInto - то же, что FindOne, но декодирует документ в значение типа T
+func FindOneInto[T any](r *Racs, filterData interface{}, sort interface{}) (T, error) {
+	return FindOneIntoContext[T](context.Background(), r, filterData, sort)
+}
+
+func FindOneIntoContext[T any](ctx context.Context, r *Racs, filterData interface{}, sort interface{}) (T, error) {
+	ctx = withOperation(ctx, "FindOneInto")
+
+	var result T
+	_, raw, err := r.readByFilterWithBody(ctx, readQuery{filter: filterData, sort: sort, limit: 1})
+	if err != nil {
+		return result, err
+	}
+
+	var resp struct {
+		Data []T `json:"data"`
+	}
+	if err := r.unmarshal(raw, &resp); err != nil {
+		return result, opError(ctx, r.buildURL("get"), err)
+	}
+	if len(resp.Data) == 0 {
+		return result, ErrNotFound
+	}
+
+	return resp.Data[0], nil
+}
+
+// isEmptyDocument - true для пустого тела, null или пустого объекта
+func isEmptyDocument(data []byte) bool {
+	switch string(bytes.TrimSpace(data)) {
+	case "", "null", "{}":
+		return true
+	}
+	return false
+}
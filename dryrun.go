@@ -0,0 +1,62 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrDryRun - возвращается методами, вызванными с контекстом из DryRun
+var ErrDryRun = errors.New("dry run: request was not sent")
+
+// dryRunKey - ключ контекста для DryRun
+type dryRunKey struct{}
+
+// DryRun - возвращает контекст, при использовании которого методы *Context полностью формируют
+// запрос (URL, заголовки, включая аутентификацию и изменения из WithRequestHook, и тело),
+// но вместо отправки сохраняют его в dst и возвращают ErrDryRun. Тело сохранённого запроса можно прочитать через req.GetBody.
+// Пример:
+//
+//	var req *http.Request
+//	_, err := r.UpdatePostByFilterContext(racs.DryRun(ctx, &req), filter, update)
+//	// errors.Is(err, racs.ErrDryRun) == true, req содержит итоговый запрос
+func DryRun(ctx context.Context, dst **http.Request) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, dst)
+}
+
+// dryRun - если контекст запроса создан DryRun, сохраняет запрос вместо отправки
+func (r *Racs) dryRun(req *http.Request) (bool, error) {
+	dst, ok := req.Context().Value(dryRunKey{}).(**http.Request)
+	if !ok || dst == nil {
+		return false, nil
+	}
+
+	// тело буферизуется, чтобы его можно было читать многократно и чтобы
+	// не блокировать горутины, формирующие потоковое тело
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return true, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+		req.ContentLength = int64(len(data))
+	}
+
+	if r.auth != nil {
+		if err := r.auth.Apply(req); err != nil {
+			return true, err
+		}
+	}
+	if err := r.runRequestHooks(req); err != nil {
+		return true, err
+	}
+
+	*dst = req
+	return true, ErrDryRun
+}
@@ -0,0 +1,229 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Custom errors
+var (
+	ErrNoUpdatesMade = errors.New("no updates were made")
+	ErrFailedDelete  = errors.New("failed to delete post")
+	ErrNotFound      = errors.New("post not found")
+
+	ErrResponseTooLarge = errors.New("response body too large")
+	ErrInvalidData      = errors.New("invalid post data")
+	ErrDuplicateKey     = errors.New("duplicate key")
+
+	// ErrNoEffectiveChange - документы найдены, но обновление не изменило их; см. WithNoEffectiveChangeError
+	ErrNoEffectiveChange = errors.New("matched documents were not modified")
+)
+
+// StatusError - ошибка, возвращаемая при ответе сервера со статусом >= 400
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+	// Response - декодированное тело ответа, если оно является валидным JSON
+	Response map[string]interface{}
+	// Server - сообщение об ошибке из тела ответа, если сервер его вернул
+	Server *ServerError
+}
+
+func (e *StatusError) Error() string {
+	if e.Server != nil {
+		return fmt.Sprintf("unexpected response status: %s: %s", e.Status, e.Server)
+	}
+	return fmt.Sprintf("unexpected response status: %s", e.Status)
+}
+
+// Is - ответ 404 соответствует ErrNotFound
+func (e *StatusError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+// Unwrap - позволяет получить *ServerError через errors.As
+func (e *StatusError) Unwrap() error {
+	if e.Server == nil {
+		return nil
+	}
+	return e.Server
+}
+
+// ServerError - сообщение об ошибке из тела ответа сервера. Распознаются конверты
+// {"error": "..."}, {"error": {"code": ..., "message": ..., "details": ...}}
+// и {"message": "...", "code": ..., "details": ...}.
+type ServerError struct {
+	Code    string
+	Message string
+	Details interface{}
+}
+
+func (e *ServerError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+	}
+	return e.Message
+}
+
+// parseServerError - достаёт сообщение об ошибке из декодированного тела ответа
+func parseServerError(resp map[string]interface{}) *ServerError {
+	envelope := resp
+	switch value := resp["error"].(type) {
+	case string:
+		if value != "" {
+			return &ServerError{Code: idString(resp["code"]), Message: value, Details: resp["details"]}
+		}
+	case map[string]interface{}:
+		envelope = value
+	}
+
+	message, _ := envelope["message"].(string)
+	if message == "" {
+		return nil
+	}
+	return &ServerError{Code: idString(envelope["code"]), Message: message, Details: envelope["details"]}
+}
+
+// TooManyRequestsError - ответ 429; RetryAfter - задержка из заголовка Retry-After
+// (0, если заголовок отсутствует). Оборачивает исходный *StatusError.
+type TooManyRequestsError struct {
+	RetryAfter time.Duration
+	Err        *StatusError
+}
+
+func (e *TooManyRequestsError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("too many requests, retry after %s", e.RetryAfter)
+	}
+	return "too many requests"
+}
+
+func (e *TooManyRequestsError) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateKeyError - нарушение уникального индекса. errors.Is(err, ErrDuplicateKey) истинно;
+// Field - имя конфликтующего поля, если сервер его сообщил. Оборачивает исходный *StatusError.
+type DuplicateKeyError struct {
+	Field string
+	Err   *StatusError
+}
+
+func (e *DuplicateKeyError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("duplicate key on field %q", e.Field)
+	}
+	return "duplicate key"
+}
+
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.Err
+}
+
+// dupKeyField - имя поля в тексте ошибки MongoDB вида "... dup key: { email: \"a@b.c\" }"
+var dupKeyField = regexp.MustCompile(`dup key: \{ ?"?([^":\s]+)"?\s*:`)
+
+// duplicateKeyError - распознаёт ошибку уникального индекса по коду 11000/11001, тексту E11000
+// или "duplicate key" в сообщении сервера и, если это она, возвращает *DuplicateKeyError.
+// Статус 409 сам по себе конфликтом ключа не считается (это может быть, например, конфликт
+// версий), а служит признаком только вместе с keyValue или keyPattern в ответе.
+func duplicateKeyError(statusErr *StatusError) *DuplicateKeyError {
+	server := statusErr.Server
+	field, hasKey := duplicateKeyField(statusErr)
+
+	duplicate := statusErr.StatusCode == http.StatusConflict && hasKey
+	if server != nil {
+		code := strings.TrimPrefix(server.Code, "E")
+		duplicate = duplicate || code == "11000" || code == "11001" ||
+			strings.Contains(server.Message, "E11000") || strings.Contains(strings.ToLower(server.Message), "duplicate key")
+	}
+	if !duplicate {
+		return nil
+	}
+
+	return &DuplicateKeyError{Field: field, Err: statusErr}
+}
+
+// duplicateKeyField - имя конфликтующего поля из keyValue/keyPattern ответа или из текста
+// сообщения; второе значение истинно, если ответ содержит keyValue или keyPattern
+func duplicateKeyField(statusErr *StatusError) (string, bool) {
+	server := statusErr.Server
+	for _, source := range []interface{}{statusErr.Response, statusErr.Response["error"], serverDetails(server)} {
+		fields, ok := source.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"keyValue", "keyPattern"} {
+			if keys, ok := fields[key].(map[string]interface{}); ok {
+				for field := range keys {
+					return field, true
+				}
+			}
+		}
+	}
+	if server != nil {
+		if match := dupKeyField.FindStringSubmatch(server.Message); match != nil {
+			return match[1], false
+		}
+	}
+	return "", false
+}
+
+// serverDetails - поле Details сообщения сервера или nil
+func serverDetails(server *ServerError) interface{} {
+	if server == nil {
+		return nil
+	}
+	return server.Details
+}
+
+// opError - добавляет к ошибке имя операции и URL запроса (без секретов), сохраняя цепочку %w
+func opError(ctx context.Context, rawURL string, err error) error {
+	return fmt.Errorf("racs %s %s: %w", operationFrom(ctx), redactURL(rawURL), err)
+}
+
+// responseError - opError для ошибки, возникшей при обработке ответа
+func responseError(res *http.Response, err error) error {
+	if res.Request == nil {
+		return err
+	}
+	return opError(res.Request.Context(), res.Request.URL.String(), err)
+}
+
+// secretParams - параметры query, значения которых скрываются в тексте ошибок
+var secretParams = []string{"key", "apikey", "api_key", "token", "access_token", "secret", "password"}
+
+// redactURL - скрывает пароль из userinfo и значения секретных параметров query
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	query := parsed.Query()
+	redacted := false
+	for key := range query {
+		for _, secret := range secretParams {
+			if strings.EqualFold(key, secret) {
+				query.Set(key, "REDACTED")
+				redacted = true
+			}
+		}
+	}
+	if redacted {
+		parsed.RawQuery = query.Encode()
+	}
+
+	return parsed.Redacted()
+}
@@ -0,0 +1,48 @@
+package racs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDuplicateKeyClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		duplicate bool
+		field     string
+	}{
+		{"mongo code", http.StatusBadRequest, `{"error":{"code":11000,"message":"E11000 duplicate key error dup key: { email: \"a@b.c\" }"}}`, true, "email"},
+		{"message only", http.StatusInternalServerError, `{"error":"E11000 duplicate key error"}`, true, ""},
+		{"conflict with key", http.StatusConflict, `{"error":"conflict","keyValue":{"sku":"x"}}`, true, "sku"},
+		{"version conflict", http.StatusConflict, `{"error":"version conflict"}`, false, ""},
+		{"empty conflict", http.StatusConflict, ``, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			r, err := NewRacs("resource", "dataset", WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = r.CreatePost(map[string]interface{}{"a": 1})
+			if got := errors.Is(err, ErrDuplicateKey); got != tt.duplicate {
+				t.Fatalf("errors.Is(%v, ErrDuplicateKey) = %v, want %v", err, got, tt.duplicate)
+			}
+			var dupErr *DuplicateKeyError
+			if errors.As(err, &dupErr) && dupErr.Field != tt.field {
+				t.Fatalf("Field = %q, want %q", dupErr.Field, tt.field)
+			}
+		})
+	}
+}
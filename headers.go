@@ -0,0 +1,62 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// contextHeader - заголовок, значение которого вычисляется из контекста запроса
+type contextHeader struct {
+	key string
+	fn  func(ctx context.Context) string
+}
+
+// WithHeaderFromContext - добавлять к каждому запросу заголовок key со значением fn(ctx),
+// например чтобы передать X-Request-Id входящего запроса. Пустое значение не отправляется;
+// непустое переопределяет статический заголовок с тем же именем.
+func WithHeaderFromContext(key string, fn func(ctx context.Context) string) Option {
+	return func(r *Racs) error {
+		if key == "" {
+			return errors.New("header key can't be empty")
+		}
+		if fn == nil {
+			return errors.New("header func can't be nil")
+		}
+		r.contextHeaders = append(r.contextHeaders[:len(r.contextHeaders):len(r.contextHeaders)], contextHeader{key: key, fn: fn})
+		return nil
+	}
+}
+
+// setContextHeaders - вычисляет заголовки из WithHeaderFromContext для запроса
+func (r *Racs) setContextHeaders(req *http.Request) {
+	for _, header := range r.contextHeaders {
+		if value := header.fn(req.Context()); value != "" {
+			req.Header.Set(header.key, value)
+		}
+	}
+}
+
+// headerCaptureKey - ключ контекста для CaptureResponseHeader
+type headerCaptureKey struct{}
+
+// CaptureResponseHeader - возвращает контекст, при использовании которого в любом методе *Context
+// заголовки последнего полученного ответа (например, X-Request-Id или лимиты запросов)
+// записываются в dst. Пример:
+//
+//	var header http.Header
+//	_, err := r.CreatePostContext(racs.CaptureResponseHeader(ctx, &header), data)
+//	requestID := header.Get("X-Request-Id")
+func CaptureResponseHeader(ctx context.Context, dst *http.Header) context.Context {
+	return context.WithValue(ctx, headerCaptureKey{}, dst)
+}
+
+// captureResponseHeader - сохраняет заголовки ответа, если контекст запроса создан CaptureResponseHeader
+func captureResponseHeader(req *http.Request, res *http.Response) {
+	if res == nil {
+		return
+	}
+	if dst, ok := req.Context().Value(headerCaptureKey{}).(*http.Header); ok && dst != nil {
+		*dst = res.Header.Clone()
+	}
+}
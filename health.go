@@ -0,0 +1,77 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Ошибки VerifyAccess
+var (
+	ErrUnreachable     = errors.New("racs server is unreachable")
+	ErrUnauthorized    = errors.New("racs credentials were rejected")
+	ErrDatasetNotFound = errors.New("racs resource or dataset not found")
+)
+
+// Ping - проверить доступность сервера HEAD-запросом к BaseURL.
+// Любой ответ со статусом ниже 500 считается признаком доступности;
+// при статусе 5xx возвращается *StatusError.
+func (r *Racs) Ping(ctx context.Context) error {
+	ctx = withOperation(ctx, "Ping")
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", r.BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	r.setHeaders(req)
+
+	res, err := r.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 500 {
+		_, err := r.readResponse(res)
+		return err
+	}
+
+	return nil
+}
+
+// VerifyAccess - проверить, что сервер доступен и учётные данные позволяют читать
+// настроенные resource и dataset, выполнив чтение одного документа. Ошибка оборачивает
+// ErrUnreachable (сетевой сбой или 5xx), ErrUnauthorized (401/403) или ErrDatasetNotFound (404)
+// вместе с исходной ошибкой; иные ошибки возвращаются без изменений.
+func (r *Racs) VerifyAccess(ctx context.Context) error {
+	ctx = withOperation(ctx, "VerifyAccess")
+
+	_, err := r.ReadPostByFilterContext(ctx, nil, nil, 1)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return fmt.Errorf("%w: %w", ErrUnreachable, err)
+		}
+		return err
+	}
+
+	switch {
+	case statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	case statusErr.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %w", ErrDatasetNotFound, err)
+	case statusErr.StatusCode >= 500:
+		return fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ResponseHook - вызывается после декодирования каждого JSON-ответа с HTTP-методом и URL запроса.
+// Может изменить resp на месте; ненулевая ошибка прерывает вызов метода.
+type ResponseHook func(method, url string, resp map[string]interface{}) error
+
+// WithResponseHook - добавить хук для обработки ответов всех методов (например, удаления
+// служебных полей или обнаружения изменений схемы). Хуки вызываются в порядке добавления.
+// Не вызывается для ответов, которые не декодируются в map (файлы, Into-варианты, потоковое чтение).
+func WithResponseHook(hook ResponseHook) Option {
+	return func(r *Racs) error {
+		if hook == nil {
+			return errors.New("response hook can't be nil")
+		}
+		r.responseHooks = append(r.responseHooks[:len(r.responseHooks):len(r.responseHooks)], hook)
+		return nil
+	}
+}
+
+// runResponseHooks - вызывает хуки из WithResponseHook до первой ошибки
+func (r *Racs) runResponseHooks(ctx context.Context, method, url string, resp map[string]interface{}) error {
+	for _, hook := range r.responseHooks {
+		if err := hook(method, url, resp); err != nil {
+			return opError(ctx, url, err)
+		}
+	}
+	return nil
+}
+
+// RequestHook - вызывается перед отправкой каждого запроса, включая повторы, после
+// аутентификации. Может изменить заголовки или тело запроса (например, подписать его);
+// ненулевая ошибка прерывает вызов метода без отправки запроса.
+type RequestHook func(req *http.Request) error
+
+// WithRequestHook - добавить хук для изменения исходящих запросов. Хуки вызываются в порядке добавления.
+func WithRequestHook(hook RequestHook) Option {
+	return func(r *Racs) error {
+		if hook == nil {
+			return errors.New("request hook can't be nil")
+		}
+		r.requestHooks = append(r.requestHooks[:len(r.requestHooks):len(r.requestHooks)], hook)
+		return nil
+	}
+}
+
+// runRequestHooks - вызывает хуки из WithRequestHook до первой ошибки
+func (r *Racs) runRequestHooks(req *http.Request) error {
+	for _, hook := range r.requestHooks {
+		if err := hook(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
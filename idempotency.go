@@ -0,0 +1,79 @@
+package racs
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// idempotencyKeyHeader - заголовок, по которому сервер распознаёт повтор запроса на создание
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotencyKey - ключ контекста для IdempotencyKey
+type idempotencyKey struct{}
+
+// IdempotencyKey - возвращает контекст, с которым CreatePostContext и CreatePostIntoContext
+// отправляют заголовок Idempotency-Key с указанным значением. Запросы с ключом повторяются
+// при сетевых сбоях и ответах 502/503/504 так же, как GET (см. WithRetry).
+// Ключ относится к одному документу: не используйте один контекст для создания разных
+// документов. CreatePostsParallel добавляет к ключу номер документа, а CreatePosts
+// ключ не отправляет.
+func IdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// WithIdempotencyKeys - автоматически генерировать Idempotency-Key (UUID v4) для каждого вызова
+// CreatePost, если ключ не передан через IdempotencyKey. Ключ одинаков для всех повторов вызова,
+// поэтому вместе с WithRetry создание документа не приводит к дубликатам.
+func WithIdempotencyKeys() Option {
+	return func(r *Racs) error {
+		r.idempotencyKeys = true
+		return nil
+	}
+}
+
+// idempotentRequest - ключ контекста, которым методы создания помечают свой запрос;
+// значение - ключ идемпотентности, отправляемый в заголовке
+type idempotentRequest struct{}
+
+// withIdempotencyKey - помечает запрос на создание ключом из IdempotencyKey или, если это
+// включено и ключа нет, сгенерированным ключом. Остальные запросы заголовок не получают,
+// даже если их контекст содержит ключ.
+func (r *Racs) withIdempotencyKey(ctx context.Context) context.Context {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	if key == "" && r.idempotencyKeys {
+		key = newUUID()
+	}
+	if key == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, idempotentRequest{}, key)
+}
+
+// itemIdempotencyKey - ключ для i-го документа многодокументной операции, производный
+// от ключа из IdempotencyKey; без ключа в контексте возвращает ctx без изменений
+func itemIdempotencyKey(ctx context.Context, i int) context.Context {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	if key == "" {
+		return ctx
+	}
+	return IdempotencyKey(ctx, key+"-"+strconv.Itoa(i))
+}
+
+// setIdempotencyKey - переносит ключ помеченного запроса на создание в заголовок
+func setIdempotencyKey(req *http.Request) {
+	if key, _ := req.Context().Value(idempotentRequest{}).(string); key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+}
+
+// newUUID - случайный UUID версии 4
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
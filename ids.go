@@ -0,0 +1,48 @@
+package racs
+
+import (
+	"encoding/hex"
+)
+
+// WithObjectIDs - передавать ID в фильтрах как ObjectID в расширенном JSON ({"$oid": "..."}),
+// если сервер хранит _id как ObjectID. Применяется к ByID и фильтрам, которые библиотека
+// строит сама (ReadPostsByIDs, DeletePostsByIDs, UpdatePostByIDIfVersion); ID, не являющиеся
+// 24-символьной hex-строкой, передаются как есть.
+func WithObjectIDs() Option {
+	return func(r *Racs) error {
+		r.objectIDs = true
+		return nil
+	}
+}
+
+// ByID - фильтр по _id в том виде, который ожидает сервер (см. WithObjectIDs),
+// например для ReadPostByFilter или UpdatePostByFilter
+func (r *Racs) ByID(id string) map[string]interface{} {
+	return map[string]interface{}{"_id": r.idValue(id)}
+}
+
+// byIDs - фильтр {"_id": {"$in": [...]}} для нескольких ID
+func (r *Racs) byIDs(ids []string) map[string]interface{} {
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = r.idValue(id)
+	}
+	return map[string]interface{}{"_id": map[string]interface{}{"$in": values}}
+}
+
+// idValue - значение ID для фильтра
+func (r *Racs) idValue(id string) interface{} {
+	if r.objectIDs && isObjectIDHex(id) {
+		return map[string]interface{}{"$oid": id}
+	}
+	return id
+}
+
+// isObjectIDHex - true для строкового представления ObjectID (24 hex-символа)
+func isObjectIDHex(id string) bool {
+	if len(id) != 24 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
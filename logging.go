@@ -0,0 +1,53 @@
+package racs
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// redactedHeaders - заголовки, значения которых не передаются в WithLogger
+var redactedHeaders = []string{"Authorization", "X-API-Key"}
+
+// RequestInfo - сведения об одной отправке запроса для WithLogger
+type RequestInfo struct {
+	Method string
+	URL    string
+	// Header - заголовки запроса; значения Authorization и X-API-Key заменены на "REDACTED"
+	Header     http.Header
+	Duration   time.Duration
+	StatusCode int
+	Err        error
+}
+
+// WithLogger - вызывать fn после каждой отправки запроса, включая повторные попытки и ошибки
+func WithLogger(fn func(RequestInfo)) Option {
+	return func(r *Racs) error {
+		if fn == nil {
+			return errors.New("logger can't be nil")
+		}
+		r.logger = fn
+		return nil
+	}
+}
+
+func newRequestInfo(req *http.Request, res *http.Response, err error, duration time.Duration) RequestInfo {
+	header := req.Header.Clone()
+	for _, key := range redactedHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, "REDACTED")
+		}
+	}
+
+	info := RequestInfo{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Header:   header,
+		Duration: duration,
+		Err:      err,
+	}
+	if res != nil {
+		info.StatusCode = res.StatusCode
+	}
+	return info
+}
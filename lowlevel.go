@@ -0,0 +1,69 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Do - выполнить запрос к произвольному эндпоинту, ещё не поддержанному библиотекой.
+// path указывается относительно BaseURL и может содержать собственные параметры query;
+// resource, dataset и параметры из WithQueryParam добавляются автоматически. body, если не nil, сериализуется в JSON.
+// Заголовки, аутентификация, повторы и хуки применяются так же, как в остальных методах.
+func (r *Racs) Do(ctx context.Context, method, path string, body interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "Do")
+
+	if method == "" {
+		return nil, errors.New(`"method" is required`)
+	}
+
+	url, err := r.resolveURL(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := r.marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewBuffer(payload)
+	}
+
+	if method != http.MethodGet && method != http.MethodHead {
+		defer r.invalidateDataset()
+	}
+
+	return r.makeRequest(ctx, method, url, reader)
+}
+
+// resolveURL - присоединяет относительный path к BaseURL и добавляет resource и dataset в query
+func (r *Racs) resolveURL(path string) (string, error) {
+	ref, err := url.Parse(strings.TrimLeft(path, "/"))
+	if err != nil {
+		return "", err
+	}
+	if ref.Scheme != "" || ref.Host != "" {
+		return "", errors.New("path must be relative to the base url")
+	}
+
+	query := ref.Query()
+	query.Set("resource", r.Resource)
+	query.Set("dataset", r.Dataset)
+	for key, values := range r.queryParams {
+		if !query.Has(key) {
+			query[key] = values
+		}
+	}
+
+	resolved := r.BaseURL
+	if ref.Path != "" {
+		resolved += "/" + ref.EscapedPath()
+	}
+	return resolved + "?" + query.Encode(), nil
+}
@@ -0,0 +1,39 @@
+package racs
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// MetricsHook - получатель метрик запросов, например адаптер к Prometheus-коллекторам
+type MetricsHook interface {
+	// ObserveRequest - вызывается после завершения каждой операции, включая неуспешные.
+	// method - имя операции (например, "CreatePost"), status - HTTP-статус
+	// последнего ответа или 0, если ответ не был получен.
+	ObserveRequest(method string, status int, duration time.Duration)
+}
+
+// WithMetricsHook - передавать метрики каждой операции в hook
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(r *Racs) error {
+		if hook == nil {
+			return errors.New("metrics hook can't be nil")
+		}
+		r.metrics = hook
+		return nil
+	}
+}
+
+// observeRequest - сообщает о завершённой операции в MetricsHook, если он задан
+func (r *Racs) observeRequest(req *http.Request, res *http.Response, start time.Time) {
+	if r.metrics == nil {
+		return
+	}
+
+	status := 0
+	if res != nil {
+		status = res.StatusCode
+	}
+	r.metrics.ObserveRequest(operationFrom(req.Context()), status, time.Since(start))
+}
@@ -0,0 +1,80 @@
+package racs
+
+import (
+	"context"
+	"errors"
+)
+
+// IncrementField - атомарно увеличить числовое поле документа на by ($inc);
+// отрицательное by уменьшает значение. Если поля нет, оно создаётся со значением by.
+func (r *Racs) IncrementField(postID, field string, by int) (map[string]interface{}, error) {
+	return r.IncrementFieldContext(context.Background(), postID, field, by)
+}
+
+func (r *Racs) IncrementFieldContext(ctx context.Context, postID, field string, by int) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "IncrementField")
+
+	if field == "" {
+		return nil, errors.New(`"field" is required`)
+	}
+
+	return r.UpdatePostByIDRawContext(ctx, postID, map[string]interface{}{
+		"$inc": map[string]interface{}{field: by},
+	})
+}
+
+// PushToArray - добавить values в конец массива field ($push с $each).
+// Если поля нет, создаётся массив из values.
+func (r *Racs) PushToArray(postID, field string, values ...interface{}) (map[string]interface{}, error) {
+	return r.PushToArrayContext(context.Background(), postID, field, values...)
+}
+
+func (r *Racs) PushToArrayContext(ctx context.Context, postID, field string, values ...interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "PushToArray")
+
+	return r.updateArray(ctx, postID, "$push", field, values)
+}
+
+// AddToSet - добавить в массив field только те из values, которых в нём ещё нет
+// ($addToSet с $each), например для списка тегов без дубликатов
+func (r *Racs) AddToSet(postID, field string, values ...interface{}) (map[string]interface{}, error) {
+	return r.AddToSetContext(context.Background(), postID, field, values...)
+}
+
+func (r *Racs) AddToSetContext(ctx context.Context, postID, field string, values ...interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "AddToSet")
+
+	return r.updateArray(ctx, postID, "$addToSet", field, values)
+}
+
+// PullFromArray - удалить из массива field все элементы, равные одному из values ($pull с $in)
+func (r *Racs) PullFromArray(postID, field string, values ...interface{}) (map[string]interface{}, error) {
+	return r.PullFromArrayContext(context.Background(), postID, field, values...)
+}
+
+func (r *Racs) PullFromArrayContext(ctx context.Context, postID, field string, values ...interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "PullFromArray")
+
+	return r.updateArray(ctx, postID, "$pull", field, values)
+}
+
+// updateArray - собирает документ обновления для оператора над массивом
+func (r *Racs) updateArray(ctx context.Context, postID, operator, field string, values []interface{}) (map[string]interface{}, error) {
+	if field == "" {
+		return nil, errors.New(`"field" is required`)
+	}
+	if len(values) == 0 {
+		return nil, errors.New(`"values" is required`)
+	}
+
+	modifier := "$each"
+	if operator == "$pull" {
+		modifier = "$in"
+	}
+
+	return r.UpdatePostByIDRawContext(ctx, postID, map[string]interface{}{
+		operator: map[string]interface{}{
+			field: map[string]interface{}{modifier: values},
+		},
+	})
+}
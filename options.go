@@ -0,0 +1,146 @@
+package racs
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option - функциональная опция для настройки Racs в NewRacs
+type Option func(*Racs) error
+
+// WithHTTPClient - использовать переданный *http.Client вместо клиента по умолчанию
+func WithHTTPClient(c *http.Client) Option {
+	return func(r *Racs) error {
+		if c == nil {
+			return errors.New("http client can't be nil")
+		}
+		r.client = c
+		r.customClient = true
+		return nil
+	}
+}
+
+// WithBaseURL - использовать вместо DefaultBaseURL адрес собственного racs-совместимого
+// сервера (или httptest.Server в тестах). URL должен быть абсолютным http(s)-адресом;
+// завершающие слэши отбрасываются.
+func WithBaseURL(u string) Option {
+	return func(r *Racs) error {
+		baseURL, err := normalizeBaseURL(u)
+		if err != nil {
+			return err
+		}
+		r.BaseURL = baseURL
+		return nil
+	}
+}
+
+// WithHeader - добавить или переопределить заголовок, отправляемый с каждым запросом
+func WithHeader(key, value string) Option {
+	return func(r *Racs) error {
+		r.Headers[key] = value
+		return nil
+	}
+}
+
+// WithTimeout - задать http.Client.Timeout для всех запросов.
+// Таймаут клиента действует вместе с дедлайном контекста в методах *Context:
+// запрос прерывается по тому из них, который истечёт раньше. В обоих случаях
+// возвращаемая ошибка оборачивает context.DeadlineExceeded. Таймаут клиента
+// применяется к каждой попытке отдельно; для ограничения вызова целиком
+// см. WithDefaultTimeout. Таймаут применяется после всех опций, поэтому действует
+// и на клиент из WithHTTPClient независимо от порядка опций.
+func WithTimeout(d time.Duration) Option {
+	return func(r *Racs) error {
+		if d < 0 {
+			return errors.New("timeout can't be negative")
+		}
+		r.timeout = &d
+		return nil
+	}
+}
+
+// applyTimeout - применяет WithTimeout к клиенту, выбранному опциями
+func (r *Racs) applyTimeout() {
+	if r.timeout != nil {
+		r.client = withClientTimeout(r.client, *r.timeout)
+	}
+}
+
+// WithUseNumber - декодировать числа в ответах как json.Number вместо float64,
+// чтобы не терять точность целых чисел больше 2^53
+func WithUseNumber() Option {
+	return func(r *Racs) error {
+		r.useNumber = true
+		return nil
+	}
+}
+
+// defaultMaxResponseSize - ограничение размера ответа по умолчанию
+const defaultMaxResponseSize = 16 << 20
+
+// WithMaxResponseSize - максимальный размер тела ответа в байтах (по умолчанию 16 МБ).
+// При превышении возвращается ошибка, оборачивающая ErrResponseTooLarge.
+// Ограничение не применяется к содержимому файлов в DownloadFile.
+func WithMaxResponseSize(n int64) Option {
+	return func(r *Racs) error {
+		if n <= 0 {
+			return errors.New("max response size must be positive")
+		}
+		r.maxResponseSize = n
+		return nil
+	}
+}
+
+// WithUserAgent - задать заголовок User-Agent (по умолчанию "racs-go-lib/<Version>")
+func WithUserAgent(ua string) Option {
+	return func(r *Racs) error {
+		if ua == "" {
+			return errors.New("user agent can't be empty")
+		}
+		r.Headers["User-Agent"] = ua
+		return nil
+	}
+}
+
+// WithQueryParam - добавить параметр query ко всем запросам (например, флаг функциональности сервера).
+// Параметры resource и dataset переопределить нельзя.
+func WithQueryParam(key, value string) Option {
+	return func(r *Racs) error {
+		if key == "" {
+			return errors.New("query param key can't be empty")
+		}
+		if key == "resource" || key == "dataset" {
+			return errors.New("query param " + key + " is reserved")
+		}
+		if r.queryParams == nil {
+			r.queryParams = url.Values{}
+		}
+		r.queryParams.Add(key, value)
+		return nil
+	}
+}
+
+// WithDefaultSort - сортировка для чтения по фильтру, если в вызове передан nil
+// (без опции используется {"_created": -1}). Подходит как map, так и SortBuilder.
+func WithDefaultSort(sort interface{}) Option {
+	return func(r *Racs) error {
+		if sort == nil {
+			return errors.New("default sort can't be nil")
+		}
+		r.defaultSort = sort
+		return nil
+	}
+}
+
+// WithDefaultLimit - лимит для чтения по фильтру, если в вызове передан 0 (без опции - 1)
+func WithDefaultLimit(n int) Option {
+	return func(r *Racs) error {
+		if n <= 0 {
+			return errors.New("default limit must be positive")
+		}
+		r.defaultLimit = n
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package racs
+
+import (
+	"context"
+	"errors"
+)
+
+// Paginator - итератор по страницам результата ReadPostByFilter
+type Paginator struct {
+	racs     *Racs
+	filter   interface{}
+	sort     interface{}
+	pageSize int
+	skip     int
+	done     bool
+}
+
+// Paginate - создаёт итератор, последовательно читающий документы страницами по pageSize.
+// Для детерминированного обхода sort должен задавать однозначный порядок.
+func (r *Racs) Paginate(filterData interface{}, sort interface{}, pageSize int) (*Paginator, error) {
+	if pageSize <= 0 {
+		return nil, errors.New(`"page_size" must be positive`)
+	}
+
+	return &Paginator{
+		racs:     r,
+		filter:   filterData,
+		sort:     sort,
+		pageSize: pageSize,
+	}, nil
+}
+
+// Next - возвращает следующую страницу документов.
+// Когда данные закончились, возвращает пустую страницу и nil.
+func (p *Paginator) Next() ([]map[string]interface{}, error) {
+	return p.NextContext(context.Background())
+}
+
+func (p *Paginator) NextContext(ctx context.Context) ([]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "Paginator.Next")
+
+	if p.done {
+		return nil, nil
+	}
+
+	resp, err := p.racs.readByFilter(ctx, readQuery{
+		filter: p.filter,
+		sort:   p.sort,
+		limit:  p.pageSize,
+		skip:   p.skip,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := responseDocuments(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skip += len(docs)
+	if len(docs) < p.pageSize {
+		p.done = true
+	}
+
+	return docs, nil
+}
+
+// Done - true, если последняя страница уже была получена
+func (p *Paginator) Done() bool {
+	return p.done
+}
+
+// ForEach - обходит все документы, подходящие под фильтр, читая их страницами по pageSize,
+// и вызывает fn для каждого документа. В памяти одновременно находится не больше одной
+// страницы. Обход останавливается на первой ошибке fn, которая и возвращается.
+func (r *Racs) ForEach(filterData interface{}, sort interface{}, pageSize int, fn func(doc map[string]interface{}) error) error {
+	return r.ForEachContext(context.Background(), filterData, sort, pageSize, fn)
+}
+
+func (r *Racs) ForEachContext(ctx context.Context, filterData interface{}, sort interface{}, pageSize int, fn func(doc map[string]interface{}) error) error {
+	ctx = withOperation(ctx, "ForEach")
+
+	if fn == nil {
+		return errors.New(`"fn" is required`)
+	}
+
+	pages, err := r.Paginate(filterData, sort, pageSize)
+	if err != nil {
+		return err
+	}
+
+	for !pages.Done() {
+		docs, err := pages.NextContext(ctx)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// forEachParallel - вызывает fn для индексов 0..n-1 не более чем в workers горутинах.
+// После отмены ctx новые задачи не запускаются; уже запущенные доводятся до конца.
+func forEachParallel(ctx context.Context, n, workers int, fn func(ctx context.Context, i int)) {
+	if workers <= 0 {
+		workers = 1
+	}
+	workers = min(workers, n)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(ctx, i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// ParallelResult - результат одной операции параллельного метода
+type ParallelResult struct {
+	Response map[string]interface{}
+	Err      error
+}
+
+// CreatePostsParallel - создать документы по одному запросу на документ, не более чем в workers
+// горутинах. Результаты возвращаются в порядке входных данных. После отмены ctx новые запросы
+// не отправляются: для них Err содержит ошибку контекста, и она же возвращается вторым значением.
+// Если ctx содержит ключ из IdempotencyKey, i-й документ отправляется с ключом "<ключ>-<i>",
+// чтобы сервер не принял разные документы за повтор одного запроса.
+func (r *Racs) CreatePostsParallel(ctx context.Context, data []map[string]interface{}, workers int) ([]ParallelResult, error) {
+	ctx = withOperation(ctx, "CreatePostsParallel")
+
+	if len(data) == 0 {
+		return nil, errors.New(`"data" is required`)
+	}
+
+	results := make([]ParallelResult, len(data))
+	started := make([]bool, len(data))
+	forEachParallel(ctx, len(data), workers, func(ctx context.Context, i int) {
+		started[i] = true
+		results[i].Response, results[i].Err = r.CreatePostContext(itemIdempotencyKey(ctx, i), data[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if !started[i] {
+				results[i].Err = err
+			}
+		}
+		return results, err
+	}
+
+	return results, nil
+}
+
+// BatchUpdateResult - суммарный результат BatchUpdateByID
+type BatchUpdateResult struct {
+	MatchedCount  int64
+	ModifiedCount int64
+	// Errors - ошибки по ID документов; обновления без ошибок в него не попадают
+	Errors map[string]error
+}
+
+// BatchUpdateByID - применить к каждому документу своё обновление (ID -> поля или операторы,
+// как в UpdatePostByID) по одному запросу на документ, не более чем в workers горутинах.
+// Счётчики суммируются по успешным обновлениям. После отмены ctx новые запросы не отправляются:
+// для них в Errors записывается ошибка контекста, и она же возвращается вторым значением.
+func (r *Racs) BatchUpdateByID(ctx context.Context, updates map[string]map[string]interface{}, workers int) (*BatchUpdateResult, error) {
+	ctx = withOperation(ctx, "BatchUpdateByID")
+
+	if len(updates) == 0 {
+		return nil, errors.New(`"updates" is required`)
+	}
+
+	ids := make([]string, 0, len(updates))
+	for id := range updates {
+		ids = append(ids, id)
+	}
+
+	var (
+		mu      sync.Mutex
+		result  = &BatchUpdateResult{Errors: make(map[string]error)}
+		started = make(map[string]bool, len(ids))
+	)
+	forEachParallel(ctx, len(ids), workers, func(ctx context.Context, i int) {
+		id := ids[i]
+		updated, err := r.UpdatePostByIDTypedContext(ctx, id, updates[id])
+
+		mu.Lock()
+		defer mu.Unlock()
+		started[id] = true
+		if err != nil {
+			result.Errors[id] = err
+			return
+		}
+		result.MatchedCount += updated.MatchedCount
+		result.ModifiedCount += updated.ModifiedCount
+	})
+
+	if err := ctx.Err(); err != nil {
+		for _, id := range ids {
+			if !started[id] {
+				result.Errors[id] = err
+			}
+		}
+		return result, err
+	}
+
+	return result, nil
+}
+
+// CreateFilesParallel - загрузить файлы по одному запросу на файл, не более чем в workers
+// горутинах. Результаты возвращаются в порядке paths. После отмены ctx новые загрузки
+// не начинаются: для них Err содержит ошибку контекста, и она же возвращается вторым значением.
+func (r *Racs) CreateFilesParallel(ctx context.Context, paths []string, workers int) ([]ParallelResult, error) {
+	ctx = withOperation(ctx, "CreateFilesParallel")
+
+	if len(paths) == 0 {
+		return nil, errors.New(`"file_paths" is required`)
+	}
+
+	results := make([]ParallelResult, len(paths))
+	started := make([]bool, len(paths))
+	forEachParallel(ctx, len(paths), workers, func(ctx context.Context, i int) {
+		started[i] = true
+		results[i].Response, results[i].Err = r.CreateFileContext(ctx, paths[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if !started[i] {
+				results[i].Err = err
+			}
+		}
+		return results, err
+	}
+
+	return results, nil
+}
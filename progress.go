@@ -0,0 +1,83 @@
+package racs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// ProgressFunc - вызывается по мере отправки содержимого файлов.
+// total равен -1, если размер хотя бы одного из файлов заранее неизвестен.
+type ProgressFunc func(bytesSent, total int64)
+
+// WithUploadProgress - сообщать о ходе загрузки файлов в CreateFile и связанных методах
+func WithUploadProgress(fn ProgressFunc) Option {
+	return func(r *Racs) error {
+		if fn == nil {
+			return errors.New("progress callback can't be nil")
+		}
+		r.progress = fn
+		return nil
+	}
+}
+
+// progressReader - считает прочитанные байты и сообщает о них в ProgressFunc
+type progressReader struct {
+	reader io.Reader
+	sent   *atomic.Int64
+	total  int64
+	fn     ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	if n > 0 {
+		p.fn(p.sent.Add(int64(n)), p.total)
+	}
+	return n, err
+}
+
+// withProgress - оборачивает источники файлов счётчиком, если задан WithUploadProgress
+func (r *Racs) withProgress(files []FileUpload) []FileUpload {
+	if r.progress == nil {
+		return files
+	}
+
+	total := int64(0)
+	for _, file := range files {
+		size := readerSize(file.Reader)
+		if size < 0 {
+			total = -1
+			break
+		}
+		total += size
+	}
+
+	sent := new(atomic.Int64)
+	wrapped := make([]FileUpload, len(files))
+	for i, file := range files {
+		wrapped[i] = file
+		wrapped[i].Reader = &progressReader{reader: file.Reader, sent: sent, total: total, fn: r.progress}
+	}
+	return wrapped
+}
+
+// readerSize - размер оставшегося содержимого источника или -1, если он неизвестен
+func readerSize(reader io.Reader) int64 {
+	switch v := reader.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
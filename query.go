@@ -0,0 +1,135 @@
+package racs
+
+import "encoding/json"
+
+// QueryBuilder - построитель фильтров в стиле MongoDB для методов *ByFilter.
+// Нулевое значение - пустой фильтр, готовый к использованию.
+type QueryBuilder struct {
+	filter map[string]interface{}
+	// operators - поля, значением которых является документ операторов, созданный построителем
+	operators map[string]map[string]interface{}
+}
+
+// Query - создаёт пустой построитель фильтра.
+// Пример: Query().Eq("status", "active").Gt("age", 18).Build()
+func Query() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+// Eq - поле равно value
+func (q *QueryBuilder) Eq(field string, value interface{}) *QueryBuilder {
+	if ops, ok := q.operators[field]; ok {
+		ops["$eq"] = value
+		return q
+	}
+	q.init()
+	q.filter[field] = value
+	return q
+}
+
+// Ne - поле не равно value
+func (q *QueryBuilder) Ne(field string, value interface{}) *QueryBuilder {
+	return q.op(field, "$ne", value)
+}
+
+// Gt - поле больше value
+func (q *QueryBuilder) Gt(field string, value interface{}) *QueryBuilder {
+	return q.op(field, "$gt", value)
+}
+
+// Gte - поле больше или равно value
+func (q *QueryBuilder) Gte(field string, value interface{}) *QueryBuilder {
+	return q.op(field, "$gte", value)
+}
+
+// Lt - поле меньше value
+func (q *QueryBuilder) Lt(field string, value interface{}) *QueryBuilder {
+	return q.op(field, "$lt", value)
+}
+
+// Lte - поле меньше или равно value
+func (q *QueryBuilder) Lte(field string, value interface{}) *QueryBuilder {
+	return q.op(field, "$lte", value)
+}
+
+// In - поле равно одному из values
+func (q *QueryBuilder) In(field string, values ...interface{}) *QueryBuilder {
+	return q.op(field, "$in", values)
+}
+
+// Nin - поле не равно ни одному из values
+func (q *QueryBuilder) Nin(field string, values ...interface{}) *QueryBuilder {
+	return q.op(field, "$nin", values)
+}
+
+// Exists - поле присутствует (exists == true) или отсутствует в документе
+func (q *QueryBuilder) Exists(field string, exists bool) *QueryBuilder {
+	return q.op(field, "$exists", exists)
+}
+
+// Regex - поле соответствует регулярному выражению; options - флаги вроде "i"
+func (q *QueryBuilder) Regex(field, pattern, options string) *QueryBuilder {
+	q.op(field, "$regex", pattern)
+	if options != "" {
+		q.op(field, "$options", options)
+	}
+	return q
+}
+
+// Or - документ соответствует хотя бы одному из фильтров
+func (q *QueryBuilder) Or(queries ...*QueryBuilder) *QueryBuilder {
+	return q.logical("$or", queries)
+}
+
+// And - документ соответствует всем фильтрам
+func (q *QueryBuilder) And(queries ...*QueryBuilder) *QueryBuilder {
+	return q.logical("$and", queries)
+}
+
+// Build - возвращает фильтр для ReadPostByFilter, UpdatePostByFilter и DeletePostByFilter
+func (q *QueryBuilder) Build() map[string]interface{} {
+	q.init()
+	return q.filter
+}
+
+// MarshalJSON - сериализует построенный фильтр, поэтому построитель можно передавать
+// без Build везде, где принимается фильтр
+func (q *QueryBuilder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.Build())
+}
+
+// op - добавляет оператор к полю, объединяя его с уже заданными операторами
+func (q *QueryBuilder) op(field, operator string, value interface{}) *QueryBuilder {
+	q.init()
+	ops, ok := q.operators[field]
+	if !ok {
+		ops = make(map[string]interface{})
+		if current, exists := q.filter[field]; exists {
+			ops["$eq"] = current
+		}
+		q.operators[field] = ops
+		q.filter[field] = ops
+	}
+	ops[operator] = value
+	return q
+}
+
+func (q *QueryBuilder) logical(operator string, queries []*QueryBuilder) *QueryBuilder {
+	q.init()
+	clauses, _ := q.filter[operator].([]map[string]interface{})
+	for _, query := range queries {
+		clauses = append(clauses, query.Build())
+	}
+	q.filter[operator] = clauses
+	return q
+}
+
+// init - создаёт внутренние map при первом изменении, чтобы нулевое значение было рабочим
+func (q *QueryBuilder) init() {
+	if q.filter == nil {
+		q.filter = make(map[string]interface{})
+	}
+	if q.operators == nil {
+		q.operators = make(map[string]map[string]interface{})
+	}
+}
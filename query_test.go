@@ -0,0 +1,33 @@
+package racs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestQueryBuilderZeroValue(t *testing.T) {
+	var q QueryBuilder
+	q.Eq("status", "active").Gt("age", 18)
+
+	var other QueryBuilder
+	q.Or(&other)
+
+	got, err := json.Marshal(&q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"$or":[{}],"age":{"$gt":18},"status":"active"}`
+	if string(got) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestQueryBuilderMarshalJSON(t *testing.T) {
+	got, err := json.Marshal(map[string]interface{}{"filter": Query().Eq("status", "active")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"filter":{"status":"active"}}`; string(got) != want {
+		t.Fatalf("json.Marshal() = %s, want %s", got, want)
+	}
+}
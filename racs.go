@@ -2,29 +2,128 @@ package racs
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// DefaultBaseURL - адрес публичного API racs.rest, используемый без WithBaseURL
+const DefaultBaseURL = "https://racs.rest/v3"
+
 type Racs struct {
 	Resource string
 	Dataset  string
 	Headers  map[string]string
 	BaseURL  string
+
+	mu     sync.RWMutex
+	client *http.Client
+
+	settings
 }
 
-// Custom errors
-var (
-	ErrNoUpdatesMade   = errors.New("no updates were made")
-	ErrFailedDelete    = errors.New("failed to delete post")
-)
+// settings - неизменяемые после создания настройки экземпляра, общие для копий из Clone
+type settings struct {
+	auth    Authenticator
+	limiter RateLimiter
+	logger  func(RequestInfo)
+	tracer  Tracer
+	metrics MetricsHook
+
+	clientTrace *httptrace.ClientTrace
+	breaker     *circuitBreaker
+	cache       *readCache
+
+	batchSize   int
+	compression bool
+	useNumber   bool
+	codec       JSONCodec
+	warnings    func(message string)
+	progress    ProgressFunc
+	silent      bool
+
+	noEffectiveChangeError bool
+	softDeleteFilter       bool
+	objectIDs              bool
+
+	maxResponseSize int64
+	timeout         *time.Duration
+	defaultTimeout  time.Duration
+	queryParams     url.Values
+	requiredFields  []string
+	contextHeaders  []contextHeader
+	responseHooks   []ResponseHook
+	requestHooks    []RequestHook
+
+	defaultSort  interface{}
+	defaultLimit int
+
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	retryDelete    bool
+
+	idempotencyKeys bool
+
+	customClient bool
+	transport    transportSettings
+}
 
 // NewRacs - конструктор для создания нового объекта Racs
-func NewRacs(resource, dataset string) (*Racs, error) {
+func NewRacs(resource, dataset string, opts ...Option) (*Racs, error) {
+	if resource == "" {
+		return nil, errors.New("resource can't be empty")
+	}
+	if dataset == "" {
+		return nil, errors.New("dataset can't be empty")
+	}
+
+	r := &Racs{
+		Resource: resource,
+		Dataset:  dataset,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"User-Agent":   defaultUserAgent,
+		},
+		BaseURL: DefaultBaseURL,
+		client:  &http.Client{},
+	}
+
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+
+	baseURL, err := normalizeBaseURL(r.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	r.BaseURL = baseURL
+	r.applyTransport()
+	r.applyTimeout()
+
+	return r, nil
+}
+
+// Clone - создать копию экземпляра для другого resource и dataset.
+// Копия использует тот же *http.Client (и пул соединений) и все настройки,
+// а заголовки копируются, поэтому дальнейшие SetHeader не влияют друг на друга.
+func (r *Racs) Clone(resource, dataset string) (*Racs, error) {
 	if resource == "" {
 		return nil, errors.New("resource can't be empty")
 	}
@@ -32,26 +131,59 @@ func NewRacs(resource, dataset string) (*Racs, error) {
 		return nil, errors.New("dataset can't be empty")
 	}
 
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	headers := make(map[string]string, len(r.Headers))
+	for key, value := range r.Headers {
+		headers[key] = value
+	}
+
 	return &Racs{
 		Resource: resource,
 		Dataset:  dataset,
-		Headers:  map[string]string{"Content-Type": "application/json"},
-		BaseURL:  "https://racs.rest/v3",
+		Headers:  headers,
+		BaseURL:  r.BaseURL,
+		client:   r.client,
+		settings: r.settings,
 	}, nil
 }
 
-func (r *Racs) CreatePost(data map[string]interface{}) (map[string]interface{}, error) {
-	if data == nil {
-		return nil, errors.New(`"data" is required`)
+// normalizeBaseURL - проверяет, что базовый URL абсолютный, и убирает завершающие слэши
+func normalizeBaseURL(raw string) (string, error) {
+	trimmed := strings.TrimRight(raw, "/")
+	if trimmed == "" {
+		return "", errors.New("base url can't be empty")
 	}
 
-	url := fmt.Sprintf("%s?resource=%s&dataset=%s", r.BaseURL, r.Resource, r.Dataset)
-	payload, err := json.Marshal(data)
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("invalid base url %q: scheme must be http or https", raw)
+	}
+	if parsed.Host == "" {
+		return "", fmt.Errorf("invalid base url %q: missing host", raw)
+	}
+
+	return trimmed, nil
+}
+
+func (r *Racs) CreatePost(data map[string]interface{}) (map[string]interface{}, error) {
+	return r.CreatePostContext(context.Background(), data)
+}
+
+func (r *Racs) CreatePostContext(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "CreatePost")
+
+	payload, err := r.createPayload(data)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := r.makeRequest("POST", url, bytes.NewBuffer(payload))
+	url := r.buildURL()
+	resp, err := r.makeRequest(r.withIdempotencyKey(ctx), "POST", url, bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -59,250 +191,1456 @@ func (r *Racs) CreatePost(data map[string]interface{}) (map[string]interface{},
 	return resp, nil
 }
 
+// createPayload - проверяет документ для создания и кодирует его в JSON
+func (r *Racs) createPayload(data map[string]interface{}) ([]byte, error) {
+	if data == nil {
+		return nil, errors.New(`"data" is required`)
+	}
+
+	if err := r.checkRequired(data); err != nil {
+		return nil, err
+	}
+	if err := Validate(data); err != nil {
+		return nil, err
+	}
+
+	payload, err := r.marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encode post: %w", err)
+	}
+	return payload, nil
+}
+
 func (r *Racs) CreateFile(filePath string) (map[string]interface{}, error) {
+	return r.CreateFileContext(context.Background(), filePath)
+}
+
+func (r *Racs) CreateFileContext(ctx context.Context, filePath string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "CreateFile")
+
 	if filePath == "" {
 		return nil, errors.New(`"file_path" is required`)
 	}
 
-	url := fmt.Sprintf("%s?resource=%s&dataset=%s", r.BaseURL, r.Resource, r.Dataset)
+	return r.CreateFilesContext(ctx, []string{filePath})
+}
+
+// CreateFiles - загрузить несколько файлов одним multipart-запросом; каждый файл
+// передаётся отдельной частью "file" со своим именем
+func (r *Racs) CreateFiles(filePaths []string) (map[string]interface{}, error) {
+	return r.CreateFilesContext(context.Background(), filePaths)
+}
 
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+func (r *Racs) CreateFilesContext(ctx context.Context, filePaths []string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "CreateFiles")
+
+	if len(filePaths) == 0 {
+		return nil, errors.New(`"file_paths" is required`)
 	}
-	defer file.Close()
 
-	body := &bytes.Buffer{}
-	writer := io.MultiWriter(body)
+	files := make([]FileUpload, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		if filePath == "" {
+			return nil, errors.New(`"file_path" is required`)
+		}
 
-	if _, err := io.Copy(writer, file); err != nil {
-		return nil, err
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		files = append(files, FileUpload{Name: filepath.Base(filePath), Reader: file})
+	}
+
+	return r.uploadFiles(ctx, files)
+}
+
+// FileUpload - файл для загрузки: имя, передаваемое в части формы, и источник содержимого.
+// Если ContentType не задан, он определяется по расширению имени, а при неизвестном
+// расширении - по первым 512 байтам содержимого.
+type FileUpload struct {
+	Name        string
+	Reader      io.Reader
+	ContentType string
+}
+
+// CreateFileFromReader - загрузить файл с именем name, читая содержимое из произвольного io.Reader
+func (r *Racs) CreateFileFromReader(name string, reader io.Reader) (map[string]interface{}, error) {
+	return r.CreateFileFromReaderContext(context.Background(), name, reader)
+}
+
+func (r *Racs) CreateFileFromReaderContext(ctx context.Context, name string, reader io.Reader) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "CreateFileFromReader")
+
+	return r.CreateFilesFromReadersContext(ctx, []FileUpload{{Name: name, Reader: reader}})
+}
+
+// CreateFilesFromReaders - загрузить несколько файлов из произвольных io.Reader одним запросом
+func (r *Racs) CreateFilesFromReaders(files []FileUpload) (map[string]interface{}, error) {
+	return r.CreateFilesFromReadersContext(context.Background(), files)
+}
+
+func (r *Racs) CreateFilesFromReadersContext(ctx context.Context, files []FileUpload) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "CreateFilesFromReaders")
+
+	if len(files) == 0 {
+		return nil, errors.New(`"files" is required`)
+	}
+	for _, file := range files {
+		if file.Name == "" {
+			return nil, errors.New(`"name" is required`)
+		}
+		if file.Reader == nil {
+			return nil, errors.New(`"reader" is required`)
+		}
 	}
 
-	req, err := http.NewRequest("POST", url, body)
+	return r.uploadFiles(ctx, files)
+}
+
+// uploadFiles - отправляет файлы потоковым multipart-запросом
+func (r *Racs) uploadFiles(ctx context.Context, files []FileUpload) (map[string]interface{}, error) {
+	url := r.buildURL()
+	files = r.withProgress(files)
+
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+
+	// тело формируется в отдельной горутине, чтобы не держать файлы в памяти
+	go func() {
+		var err error
+		for _, file := range files {
+			contentType, reader := file.ContentType, file.Reader
+			if contentType == "" {
+				if contentType, reader, err = detectContentType(file.Name, reader); err != nil {
+					break
+				}
+			}
+
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(file.Name)))
+			header.Set("Content-Type", contentType)
+
+			var part io.Writer
+			if part, err = form.CreatePart(header); err != nil {
+				break
+			}
+			if _, err = io.Copy(part, reader); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		writer.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
+		body.Close()
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "multipart/form-data")
+	r.setHeaders(req)
+	req.Header.Set("Content-Type", form.FormDataContentType())
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := r.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
+	return r.decodeResponse(res)
+}
+
+// quoteEscaper - экранирует имя файла в Content-Disposition так же, как multipart.Writer.CreateFormFile
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// detectContentType - определяет MIME-тип файла по расширению или, если оно неизвестно,
+// по первым 512 байтам содержимого; прочитанные байты возвращаются в начало reader
+func detectContentType(name string, reader io.Reader) (string, io.Reader, error) {
+	if contentType := mime.TypeByExtension(filepath.Ext(name)); contentType != "" {
+		return contentType, reader, nil
 	}
 
-	return result, nil
+	head := make([]byte, 512)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+
+	return http.DetectContentType(head), io.MultiReader(bytes.NewReader(head), reader), nil
 }
 
 func (r *Racs) ReadPostByID(postID string) (map[string]interface{}, error) {
+	return r.ReadPostByIDContext(context.Background(), postID)
+}
+
+func (r *Racs) ReadPostByIDContext(ctx context.Context, postID string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostByID")
+
+	resp, _, err := r.readPostByID(ctx, postID)
+	return resp, err
+}
+
+// ReadPostByIDWithBody - то же, что ReadPostByID, но дополнительно возвращает исходное тело ответа,
+// например для повторного декодирования с json.Number или в собственный тип
+func (r *Racs) ReadPostByIDWithBody(postID string) (map[string]interface{}, []byte, error) {
+	return r.ReadPostByIDWithBodyContext(context.Background(), postID)
+}
+
+func (r *Racs) ReadPostByIDWithBodyContext(ctx context.Context, postID string) (map[string]interface{}, []byte, error) {
+	ctx = withOperation(ctx, "ReadPostByIDWithBody")
+
+	return r.readPostByID(ctx, postID)
+}
+
+func (r *Racs) readPostByID(ctx context.Context, postID string) (map[string]interface{}, []byte, error) {
 	if postID == "" {
-		return nil, errors.New(`"post_id" is required`)
+		return nil, nil, errors.New(`"post_id" is required`)
 	}
 
-	url := fmt.Sprintf("%s/%s?resource=%s&dataset=%s", r.BaseURL, postID, r.Resource, r.Dataset)
-	resp, err := r.makeRequest("GET", url, nil)
+	url := r.buildURL(postID)
+	data, err := r.fetchPostByID(ctx, postID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return resp, nil
-}
-
-func (r *Racs) ReadPostByFilter(filterData interface{}, sort interface{}, limit int) (map[string]interface{}, error) {
-	if filterData == nil {
-		filterData = make(map[string]interface{})
+	resp, err := r.decodeMap(data)
+	if err != nil {
+		return nil, nil, opError(ctx, url, err)
 	}
-	if sort == nil {
-		sort = map[string]int{"_created": -1}
+	if err := r.runResponseHooks(ctx, "GET", url, resp); err != nil {
+		return nil, nil, err
 	}
-	if limit == 0 {
-		limit = 1
+
+	if len(resp) == 0 || r.softDeleted(resp) {
+		return nil, nil, ErrNotFound
 	}
 
-	url := fmt.Sprintf("%s/get?resource=%s&dataset=%s", r.BaseURL, r.Resource, r.Dataset)
-	payload, err := json.Marshal(map[string]interface{}{
-		"filter": filterData,
-		"sort":   sort,
-		"limit":  limit,
-	})
-	if err != nil {
-		return nil, err
+	return resp, data, nil
+}
+
+// fetchPostByID - тело ответа на чтение документа по ID из WithReadCache или от сервера
+func (r *Racs) fetchPostByID(ctx context.Context, postID string) ([]byte, error) {
+	if data := r.cachedPost(ctx, postID); data != nil {
+		return data, nil
 	}
 
-	resp, err := r.makeRequest("POST", url, bytes.NewBuffer(payload))
+	data, err := r.makeRawRequest(ctx, "GET", r.buildURL(postID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	if !isEmptyDocument(data) {
+		r.cachePost(postID, data)
+	}
+	return data, nil
 }
 
-func (r *Racs) ReadFileByID(postID string) (map[string]interface{}, error) {
+// Exists - проверить существование документа с помощью HEAD-запроса без загрузки его содержимого
+func (r *Racs) Exists(postID string) (bool, error) {
+	return r.ExistsContext(context.Background(), postID)
+}
+
+func (r *Racs) ExistsContext(ctx context.Context, postID string) (bool, error) {
+	ctx = withOperation(ctx, "Exists")
+
 	if postID == "" {
-		return nil, errors.New(`"post_id" is required`)
+		return false, errors.New(`"post_id" is required`)
 	}
 
-	url := fmt.Sprintf("%s/file/%s?resource=%s&dataset=%s", r.BaseURL, postID, r.Resource, r.Dataset)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "HEAD", r.buildURL(postID), nil)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	req.Header.Set("Accept", "application/octet-stream")
+	r.setHeaders(req)
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := r.do(req)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
 	defer res.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if _, err := r.readResponse(res); err != nil {
+		return false, err
 	}
 
-	return result, nil
+	return true, nil
 }
 
-func (r *Racs) UpdatePostByID(postID string, updateOptions map[string]interface{}) (map[string]interface{}, error) {
-	if postID == "" {
-		return nil, errors.New(`"post_id" is required`)
-	}
-	if updateOptions == nil {
-		return nil, errors.New(`"update_options" is required`)
-	}
+func (r *Racs) ReadPostByFilter(filterData interface{}, sort interface{}, limit int) (map[string]interface{}, error) {
+	return r.ReadPostByFilterContext(context.Background(), filterData, sort, limit)
+}
 
-	url := fmt.Sprintf("%s/%s?resource=%s&dataset=%s", r.BaseURL, postID, r.Resource, r.Dataset)
-	payload, err := json.Marshal(map[string]interface{}{
-		"update": map[string]interface{}{
-			"$set": updateOptions,
-		},
-	})
+func (r *Racs) ReadPostByFilterContext(ctx context.Context, filterData interface{}, sort interface{}, limit int) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostByFilter")
+
+	return r.readByFilter(ctx, readQuery{filter: filterData, sort: sort, limit: limit})
+}
+
+// ReadPostsByFilter - то же, что ReadPostByFilter, но возвращает сразу массив документов из поля "data"
+func (r *Racs) ReadPostsByFilter(filterData interface{}, sort interface{}, limit int) ([]map[string]interface{}, error) {
+	return r.ReadPostsByFilterContext(context.Background(), filterData, sort, limit)
+}
+
+func (r *Racs) ReadPostsByFilterContext(ctx context.Context, filterData interface{}, sort interface{}, limit int) ([]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostsByFilter")
+
+	resp, err := r.readByFilter(ctx, readQuery{filter: filterData, sort: sort, limit: limit})
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := r.makeRequest("PATCH", url, bytes.NewBuffer(payload))
+	return responseDocuments(resp)
+}
+
+// FindOne - прочитать первый документ, подходящий под фильтр, с учётом сортировки.
+// Если ни один документ не найден, возвращается ErrNotFound.
+func (r *Racs) FindOne(filterData interface{}, sort interface{}) (map[string]interface{}, error) {
+	return r.FindOneContext(context.Background(), filterData, sort)
+}
+
+func (r *Racs) FindOneContext(ctx context.Context, filterData interface{}, sort interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "FindOne")
+
+	docs, err := r.ReadPostsByFilterContext(ctx, filterData, sort, 1)
 	if err != nil {
 		return nil, err
 	}
-
-	if resp["matchedCount"].(float64) == 0 && resp["modifiedCount"].(float64) == 0 {
-		return nil, ErrNoUpdatesMade
+	if len(docs) == 0 {
+		return nil, ErrNotFound
 	}
 
-	if resp["matchedCount"].(float64) > resp["modifiedCount"].(float64) {
-		fmt.Println("Warning: matchedCount is greater than modifiedCount.")
-	}
+	return docs[0], nil
+}
 
-	return resp, nil
+// ReadPostByFilterWithProjection - то же, что ReadPostByFilter, но возвращает только поля,
+// указанные в projection, например {"name": 1, "email": 1}. Исключающая проекция
+// передаётся как есть: {"_id": 0} возвращает документы без _id.
+func (r *Racs) ReadPostByFilterWithProjection(filterData interface{}, sort interface{}, limit int, projection map[string]int) (map[string]interface{}, error) {
+	return r.ReadPostByFilterWithProjectionContext(context.Background(), filterData, sort, limit, projection)
 }
 
-func (r *Racs) UpdatePostByFilter(filterData, updateOptions map[string]interface{}) (map[string]interface{}, error) {
-	if filterData == nil {
-		return nil, errors.New(`"filter_data" is required`)
-	}
-	if updateOptions == nil {
-		return nil, errors.New(`"update_options" is required`)
+func (r *Racs) ReadPostByFilterWithProjectionContext(ctx context.Context, filterData interface{}, sort interface{}, limit int, projection map[string]int) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostByFilterWithProjection")
+
+	q := readQuery{filter: filterData, sort: sort, limit: limit}
+	if len(projection) > 0 {
+		q.projection = projection
 	}
+	return r.readByFilter(ctx, q)
+}
 
-	url := fmt.Sprintf("%s?resource=%s&dataset=%s", r.BaseURL, r.Resource, r.Dataset)
-	payload, err := json.Marshal(map[string]interface{}{
-		"filter": filterData,
-		"update": map[string]interface{}{
-			"$set": updateOptions,
-		},
-	})
+// ReadPostsByFilterWithProjection - то же, что ReadPostByFilterWithProjection, но возвращает
+// сразу массив документов из поля "data", например для выгрузки без _id с projection {"_id": 0}
+func (r *Racs) ReadPostsByFilterWithProjection(filterData interface{}, sort interface{}, limit int, projection map[string]int) ([]map[string]interface{}, error) {
+	return r.ReadPostsByFilterWithProjectionContext(context.Background(), filterData, sort, limit, projection)
+}
+
+func (r *Racs) ReadPostsByFilterWithProjectionContext(ctx context.Context, filterData interface{}, sort interface{}, limit int, projection map[string]int) ([]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadPostsByFilterWithProjection")
+
+	resp, err := r.ReadPostByFilterWithProjectionContext(ctx, filterData, sort, limit, projection)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := r.makeRequest("PATCH", url, bytes.NewBuffer(payload))
+	return responseDocuments(resp)
+}
+
+// readQuery - параметры запроса на чтение по фильтру
+type readQuery struct {
+	filter     interface{}
+	sort       interface{}
+	limit      int
+	skip       int
+	projection interface{}
+}
+
+// ReadPostByFilterWithBody - то же, что ReadPostByFilter, но дополнительно возвращает исходное тело ответа
+func (r *Racs) ReadPostByFilterWithBody(filterData interface{}, sort interface{}, limit int) (map[string]interface{}, []byte, error) {
+	return r.ReadPostByFilterWithBodyContext(context.Background(), filterData, sort, limit)
+}
+
+func (r *Racs) ReadPostByFilterWithBodyContext(ctx context.Context, filterData interface{}, sort interface{}, limit int) (map[string]interface{}, []byte, error) {
+	ctx = withOperation(ctx, "ReadPostByFilterWithBody")
+
+	return r.readByFilterWithBody(ctx, readQuery{filter: filterData, sort: sort, limit: limit})
+}
+
+func (r *Racs) readByFilter(ctx context.Context, q readQuery) (map[string]interface{}, error) {
+	resp, _, err := r.readByFilterWithBody(ctx, q)
+	return resp, err
+}
+
+func (r *Racs) readByFilterWithBody(ctx context.Context, q readQuery) (map[string]interface{}, []byte, error) {
+	url := r.buildURL("get")
+	payload, err := r.marshal(r.readRequest(q))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if resp["matchedCount"].(float64) == 0 && resp["modifiedCount"].(float64) == 0 {
-		return nil, ErrNoUpdatesMade
+	return r.makeRequestWithBody(ctx, "POST", url, bytes.NewBuffer(payload))
+}
+
+// readRequest - тело запроса на чтение по фильтру с подставленными значениями по умолчанию
+func (r *Racs) readRequest(q readQuery) map[string]interface{} {
+	q.filter = r.liveFilter(q.filter)
+	if q.filter == nil {
+		q.filter = make(map[string]interface{})
+	}
+	if q.sort == nil {
+		q.sort = r.defaultSort
+	}
+	if q.sort == nil {
+		q.sort = map[string]int{"_created": -1}
+	}
+	if q.limit == 0 {
+		q.limit = r.defaultLimit
+	}
+	if q.limit == 0 {
+		q.limit = 1
 	}
 
-	if resp["matchedCount"].(float64) > resp["modifiedCount"].(float64) {
-		fmt.Println("Warning: matchedCount is greater than modifiedCount.")
+	request := map[string]interface{}{
+		"filter": q.filter,
+		"sort":   q.sort,
+		"limit":  q.limit,
 	}
+	if q.skip > 0 {
+		request["skip"] = q.skip
+	}
+	if q.projection != nil {
+		request["projection"] = q.projection
+	}
+	return request
+}
 
-	return resp, nil
+func (r *Racs) ReadFileByID(postID string) (map[string]interface{}, error) {
+	return r.ReadFileByIDContext(context.Background(), postID)
 }
 
-func (r *Racs) DeletePostByID(postID string) (map[string]interface{}, error) {
+func (r *Racs) ReadFileByIDContext(ctx context.Context, postID string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadFileByID")
+
 	if postID == "" {
 		return nil, errors.New(`"post_id" is required`)
 	}
 
-	url := fmt.Sprintf("%s/%s?resource=%s&dataset=%s", r.BaseURL, postID, r.Resource, r.Dataset)
-	resp, err := r.makeRequest("DELETE", url, nil)
+	req, err := r.newFileRequest(ctx, "GET", postID)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp["deletedCount"].(float64) == 0 {
-		return nil, ErrFailedDelete
+	res, err := r.do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer res.Body.Close()
 
-	return resp, nil
+	return r.decodeResponse(res)
 }
 
-func (r *Racs) DeletePostByFilter(filterData map[string]interface{}) (map[string]interface{}, error) {
-	if filterData == nil {
-		return nil, errors.New(`"filter_data" is required`)
+// DownloadFile - потоково записывает содержимое файла в w.
+// Возвращает количество записанных байт и Content-Type ответа.
+func (r *Racs) DownloadFile(postID string, w io.Writer) (int64, string, error) {
+	return r.DownloadFileContext(context.Background(), postID, w)
+}
+
+func (r *Racs) DownloadFileContext(ctx context.Context, postID string, w io.Writer) (int64, string, error) {
+	ctx = withOperation(ctx, "DownloadFile")
+
+	if postID == "" {
+		return 0, "", errors.New(`"post_id" is required`)
+	}
+	if w == nil {
+		return 0, "", errors.New(`"writer" is required`)
 	}
 
-	url := fmt.Sprintf("%s?resource=%s&dataset=%s", r.BaseURL, r.Resource, r.Dataset)
-	payload, err := json.Marshal(map[string]interface{}{
-		"filter": filterData,
-	})
+	req, err := r.newFileRequest(ctx, "GET", postID)
 	if err != nil {
-		return nil, err
+		return 0, "", err
 	}
 
-	resp, err := r.makeRequest("DELETE", url, bytes.NewBuffer(payload))
+	res, err := r.do(req)
 	if err != nil {
-		return nil, err
+		return 0, "", err
 	}
+	defer res.Body.Close()
 
-	if resp["deletedCount"].(float64) == 0 {
-		return nil, ErrFailedDelete
+	if res.StatusCode >= 400 {
+		_, err := r.readResponse(res)
+		return 0, "", err
 	}
 
-	return resp, nil
+	written, err := io.Copy(w, res.Body)
+	if err != nil {
+		return written, "", responseError(res, err)
+	}
+
+	return written, res.Header.Get("Content-Type"), nil
 }
 
-func (r *Racs) makeRequest(method, url string, body io.Reader) (map[string]interface{}, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, err
+// DownloadFileRange - потоково записывает в w байты файла с start по end включительно
+// (end < 0 - до конца файла), например для возобновления прерванной загрузки.
+// Возвращает количество записанных байт и полный размер файла из Content-Range
+// (-1, если сервер его не сообщил).
+func (r *Racs) DownloadFileRange(postID string, start, end int64, w io.Writer) (int64, int64, error) {
+	return r.DownloadFileRangeContext(context.Background(), postID, start, end, w)
+}
+
+func (r *Racs) DownloadFileRangeContext(ctx context.Context, postID string, start, end int64, w io.Writer) (int64, int64, error) {
+	ctx = withOperation(ctx, "DownloadFileRange")
+
+	if postID == "" {
+		return 0, 0, errors.New(`"post_id" is required`)
+	}
+	if w == nil {
+		return 0, 0, errors.New(`"writer" is required`)
+	}
+	if start < 0 || (end >= 0 && end < start) {
+		return 0, 0, fmt.Errorf("invalid range %d-%d", start, end)
 	}
 
-	for key, value := range r.Headers {
-		req.Header.Set(key, value)
+	req, err := r.newFileRequest(ctx, "GET", postID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end >= 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	} else {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", start))
 	}
 
-	res, err := client.Do(req)
+	res, err := r.do(req)
 	if err != nil {
-		return nil, err
+		return 0, 0, err
 	}
 	defer res.Body.Close()
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
+	if res.StatusCode >= 400 {
+		_, err := r.readResponse(res)
+		return 0, 0, err
+	}
+	if res.StatusCode != http.StatusPartialContent {
+		return 0, 0, responseError(res, fmt.Errorf("range request not supported: %s", res.Status))
 	}
 
-	return result, nil
+	written, err := io.Copy(w, res.Body)
+	if err != nil {
+		return written, 0, responseError(res, err)
+	}
+
+	return written, contentRangeTotal(res.Header.Get("Content-Range")), nil
+}
+
+// newFileRequest - запрос к содержимому файла /file/{postID} с заголовками экземпляра,
+// без Content-Type и с Accept: application/octet-stream
+func (r *Racs) newFileRequest(ctx context.Context, method, postID string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.buildURL("file", postID), nil)
+	if err != nil {
+		return nil, err
+	}
+	r.setHeaders(req)
+	req.Header.Del("Content-Type")
+	req.Header.Set("Accept", "application/octet-stream")
+
+	return req, nil
+}
+
+// contentRangeTotal - полный размер из заголовка вида "bytes 0-99/1234"; -1, если он неизвестен
+func contentRangeTotal(value string) int64 {
+	_, total, ok := strings.Cut(value, "/")
+	if !ok {
+		return -1
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// ReadFileMetadata - получить сведения о файле HEAD-запросом, не загружая содержимое.
+// Возвращает ключи "name", "size" (int64), "contentType" и "uploadDate" (time.Time);
+// ключи, которые сервер не сообщил, отсутствуют.
+func (r *Racs) ReadFileMetadata(postID string) (map[string]interface{}, error) {
+	return r.ReadFileMetadataContext(context.Background(), postID)
+}
+
+func (r *Racs) ReadFileMetadataContext(ctx context.Context, postID string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReadFileMetadata")
+
+	if postID == "" {
+		return nil, errors.New(`"post_id" is required`)
+	}
+
+	req, err := r.newFileRequest(ctx, "HEAD", postID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if _, err := r.readResponse(res); err != nil {
+		return nil, err
+	}
+
+	metadata := make(map[string]interface{})
+	if _, params, err := mime.ParseMediaType(res.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		metadata["name"] = params["filename"]
+	}
+	if res.ContentLength >= 0 {
+		metadata["size"] = res.ContentLength
+	}
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		metadata["contentType"] = contentType
+	}
+	if modified, err := http.ParseTime(res.Header.Get("Last-Modified")); err == nil {
+		metadata["uploadDate"] = modified
+	}
+
+	return metadata, nil
+}
+
+func (r *Racs) UpdatePostByID(postID string, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	return r.UpdatePostByIDContext(context.Background(), postID, updateOptions)
+}
+
+func (r *Racs) UpdatePostByIDContext(ctx context.Context, postID string, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpdatePostByID")
+
+	if updateOptions == nil {
+		return nil, errors.New(`"update_options" is required`)
+	}
+
+	update, err := setUpdate(updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateByID(ctx, postID, map[string]interface{}{
+		"update": update,
+	})
+}
+
+// ReturnDocument - какую версию документа вернуть после обновления
+type ReturnDocument string
+
+const (
+	// ReturnDocumentBefore - документ до применения обновления
+	ReturnDocumentBefore ReturnDocument = "before"
+	// ReturnDocumentAfter - документ после применения обновления
+	ReturnDocumentAfter ReturnDocument = "after"
+)
+
+// UpdatePostByIDReturning - то же, что UpdatePostByID, но дополнительно возвращает документ
+// в версии returnDocument (аналог findOneAndUpdate), избавляя от повторного чтения
+func (r *Racs) UpdatePostByIDReturning(postID string, updateOptions map[string]interface{}, returnDocument ReturnDocument) (map[string]interface{}, map[string]interface{}, error) {
+	return r.UpdatePostByIDReturningContext(context.Background(), postID, updateOptions, returnDocument)
+}
+
+func (r *Racs) UpdatePostByIDReturningContext(ctx context.Context, postID string, updateOptions map[string]interface{}, returnDocument ReturnDocument) (map[string]interface{}, map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpdatePostByIDReturning")
+
+	if updateOptions == nil {
+		return nil, nil, errors.New(`"update_options" is required`)
+	}
+	if returnDocument != ReturnDocumentBefore && returnDocument != ReturnDocumentAfter {
+		return nil, nil, fmt.Errorf("invalid return document %q", returnDocument)
+	}
+
+	update, err := setUpdate(updateOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := r.updateByID(ctx, postID, map[string]interface{}{
+		"update":         update,
+		"returnDocument": returnDocument,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := responseDocument(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, doc, nil
+}
+
+// UpdatePostByIDRaw - обновить документ по ID, передав документ обновления как есть,
+// например {"$inc": {"views": 1}}
+func (r *Racs) UpdatePostByIDRaw(postID string, update map[string]interface{}) (map[string]interface{}, error) {
+	return r.UpdatePostByIDRawContext(context.Background(), postID, update)
+}
+
+func (r *Racs) UpdatePostByIDRawContext(ctx context.Context, postID string, update map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpdatePostByIDRaw")
+
+	if update == nil {
+		return nil, errors.New(`"update" is required`)
+	}
+
+	return r.updateByID(ctx, postID, map[string]interface{}{
+		"update": update,
+	})
+}
+
+// ReplacePostByID - полностью заменить содержимое документа на doc (без слияния полей, как при $set).
+// Если документ с таким ID не найден, возвращается ErrNoUpdatesMade.
+func (r *Racs) ReplacePostByID(postID string, doc map[string]interface{}) (map[string]interface{}, error) {
+	return r.ReplacePostByIDContext(context.Background(), postID, doc)
+}
+
+func (r *Racs) ReplacePostByIDContext(ctx context.Context, postID string, doc map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "ReplacePostByID")
+
+	if postID == "" {
+		return nil, errors.New(`"post_id" is required`)
+	}
+	if doc == nil {
+		return nil, errors.New(`"doc" is required`)
+	}
+
+	url := r.buildURL(postID)
+	payload, err := r.marshal(map[string]interface{}{
+		"replacement": doc,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.invalidatePost(postID)
+	resp, err := r.makeRequest(ctx, "PUT", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := responseCount(resp, "matchedCount")
+	if err != nil {
+		return nil, err
+	}
+
+	if matched == 0 {
+		return nil, ErrNoUpdatesMade
+	}
+
+	return resp, nil
+}
+
+func (r *Racs) UpdatePostByFilter(filterData, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	return r.UpdatePostByFilterContext(context.Background(), filterData, updateOptions)
+}
+
+func (r *Racs) UpdatePostByFilterContext(ctx context.Context, filterData, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpdatePostByFilter")
+
+	if updateOptions == nil {
+		return nil, errors.New(`"update_options" is required`)
+	}
+
+	update, err := setUpdate(updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateByFilter(ctx, filterData, update, false)
+}
+
+// UpdatePostByFilterRaw - обновить документы по фильтру, передав документ обновления как есть
+func (r *Racs) UpdatePostByFilterRaw(filterData, update map[string]interface{}) (map[string]interface{}, error) {
+	return r.UpdatePostByFilterRawContext(context.Background(), filterData, update)
+}
+
+func (r *Racs) UpdatePostByFilterRawContext(ctx context.Context, filterData, update map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpdatePostByFilterRaw")
+
+	if update == nil {
+		return nil, errors.New(`"update" is required`)
+	}
+
+	return r.updateByFilter(ctx, filterData, update, false)
+}
+
+// UpsertPostByFilter - обновить документы по фильтру или создать новый из фильтра и обновления,
+// если ни один документ не найден. Идентификатор созданного документа возвращается в поле "upsertedId".
+func (r *Racs) UpsertPostByFilter(filterData, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	return r.UpsertPostByFilterContext(context.Background(), filterData, updateOptions)
+}
+
+func (r *Racs) UpsertPostByFilterContext(ctx context.Context, filterData, updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "UpsertPostByFilter")
+
+	if updateOptions == nil {
+		return nil, errors.New(`"update_options" is required`)
+	}
+
+	update, err := setUpdate(updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateByFilter(ctx, filterData, update, true)
+}
+
+// updateByID - отправляет PATCH по ID; request - тело запроса с ключом "update" и дополнительными параметрами
+func (r *Racs) updateByID(ctx context.Context, postID string, request map[string]interface{}) (map[string]interface{}, error) {
+	if postID == "" {
+		return nil, errors.New(`"post_id" is required`)
+	}
+
+	url := r.buildURL(postID)
+	payload, err := r.marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.invalidatePost(postID)
+	resp, err := r.makeRequest(ctx, "PATCH", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.checkUpdateCounts(resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *Racs) updateByFilter(ctx context.Context, filterData, update map[string]interface{}, upsert bool) (map[string]interface{}, error) {
+	if filterData == nil {
+		return nil, errors.New(`"filter_data" is required`)
+	}
+
+	url := r.buildURL()
+	request := map[string]interface{}{
+		"filter": filterData,
+		"update": update,
+	}
+	if upsert {
+		request["upsert"] = true
+	}
+	payload, err := r.marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.invalidateDataset()
+	resp, err := r.makeRequest(ctx, "PATCH", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	if upsert && resp["upsertedId"] != nil {
+		return resp, nil
+	}
+
+	if err := r.checkUpdateCounts(resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// setUpdate - оборачивает поля в $set. Если ключи уже являются операторами
+// обновления ($inc, $push и т.п.), документ передаётся как есть, чтобы не получить
+// {"$set": {"$inc": ...}}; смешивать операторы и обычные поля нельзя.
+func setUpdate(updateOptions map[string]interface{}) (map[string]interface{}, error) {
+	operators := 0
+	for key := range updateOptions {
+		if strings.HasPrefix(key, "$") {
+			operators++
+		}
+	}
+
+	switch operators {
+	case 0:
+		return map[string]interface{}{"$set": updateOptions}, nil
+	case len(updateOptions):
+		return updateOptions, nil
+	default:
+		return nil, errors.New(`"update_options" can't mix update operators and plain fields`)
+	}
+}
+
+// checkUpdateCounts - проверяет счётчики matchedCount/modifiedCount в ответе на обновление
+func (r *Racs) checkUpdateCounts(resp map[string]interface{}) error {
+	matched, err := responseCount(resp, "matchedCount")
+	if err != nil {
+		return err
+	}
+	modified, err := responseCount(resp, "modifiedCount")
+	if err != nil {
+		return err
+	}
+
+	if matched == 0 && modified == 0 {
+		return ErrNoUpdatesMade
+	}
+
+	if matched > modified {
+		if r.noEffectiveChangeError && modified == 0 {
+			return ErrNoEffectiveChange
+		}
+		r.warn("matchedCount is greater than modifiedCount.")
+	}
+
+	return nil
+}
+
+func (r *Racs) DeletePostByID(postID string) (map[string]interface{}, error) {
+	return r.DeletePostByIDContext(context.Background(), postID)
+}
+
+func (r *Racs) DeletePostByIDContext(ctx context.Context, postID string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "DeletePostByID")
+
+	if postID == "" {
+		return nil, errors.New(`"post_id" is required`)
+	}
+
+	url := r.buildURL(postID)
+	defer r.invalidatePost(postID)
+	resp, err := r.makeRequest(ctx, "DELETE", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// пустой успешный ответ (например, 204 No Content) означает, что удаление выполнено
+	if len(resp) == 0 {
+		return resp, nil
+	}
+
+	deleted, err := responseCount(resp, "deletedCount")
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted == 0 {
+		return nil, ErrFailedDelete
+	}
+
+	return resp, nil
+}
+
+func (r *Racs) DeletePostByFilter(filterData map[string]interface{}) (map[string]interface{}, error) {
+	return r.DeletePostByFilterContext(context.Background(), filterData)
+}
+
+func (r *Racs) DeletePostByFilterContext(ctx context.Context, filterData map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "DeletePostByFilter")
+
+	if filterData == nil {
+		return nil, errors.New(`"filter_data" is required`)
+	}
+
+	url := r.buildURL()
+	payload, err := r.marshal(map[string]interface{}{
+		"filter": filterData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.invalidateDataset()
+	resp, err := r.makeRequest(ctx, "DELETE", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	// пустой успешный ответ (например, 204 No Content) означает, что удаление выполнено
+	if len(resp) == 0 {
+		return resp, nil
+	}
+
+	deleted, err := responseCount(resp, "deletedCount")
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted == 0 {
+		return nil, ErrFailedDelete
+	}
+
+	return resp, nil
+}
+
+// FindOneAndDelete - атомарно удалить первый документ, подходящий под фильтр (с учётом sort),
+// и вернуть его содержимое. Если ничего не найдено, возвращается ErrNotFound.
+func (r *Racs) FindOneAndDelete(filterData, sort map[string]interface{}) (map[string]interface{}, error) {
+	return r.FindOneAndDeleteContext(context.Background(), filterData, sort)
+}
+
+func (r *Racs) FindOneAndDeleteContext(ctx context.Context, filterData, sort map[string]interface{}) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "FindOneAndDelete")
+
+	if filterData == nil {
+		return nil, errors.New(`"filter_data" is required`)
+	}
+
+	url := r.buildURL("findOneAndDelete")
+	request := map[string]interface{}{
+		"filter": filterData,
+	}
+	if sort != nil {
+		request["sort"] = sort
+	}
+	payload, err := r.marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.invalidateDataset()
+	resp, err := r.makeRequest(ctx, "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return responseDocument(resp)
+}
+
+func (r *Racs) makeRequest(ctx context.Context, method, url string, body io.Reader) (map[string]interface{}, error) {
+	resp, _, err := r.makeRequestWithBody(ctx, method, url, body)
+	return resp, err
+}
+
+// makeRequestWithBody - выполняет запрос и возвращает декодированный ответ вместе с исходным телом
+func (r *Racs) makeRequestWithBody(ctx context.Context, method, url string, body io.Reader) (map[string]interface{}, []byte, error) {
+	data, err := r.makeRawRequest(ctx, method, url, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := r.decodeMap(data)
+	if err != nil {
+		return nil, nil, opError(ctx, url, err)
+	}
+
+	if err := r.runResponseHooks(ctx, method, url, resp); err != nil {
+		return nil, nil, err
+	}
+
+	return resp, data, nil
+}
+
+// decodeMap - декодирует тело ответа в map; для пустого тела возвращается пустая map, а не nil
+func (r *Racs) decodeMap(data []byte) (map[string]interface{}, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	var result map[string]interface{}
+	if err := r.unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// unmarshal - декодирует JSON кодеком экземпляра с учётом WithUseNumber
+func (r *Racs) unmarshal(data []byte, v interface{}) error {
+	if r.codec != nil {
+		return r.codec.Unmarshal(data, v)
+	}
+	if !r.useNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level value")
+	}
+	return nil
+}
+
+// makeRawRequest - выполняет запрос и возвращает тело ответа без декодирования
+func (r *Racs) makeRawRequest(ctx context.Context, method, url string, body io.Reader) ([]byte, error) {
+	if r.compression && body != nil {
+		compressed, err := compressBody(body)
+		if err != nil {
+			return nil, err
+		}
+		body = compressed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.setHeaders(req)
+	setIdempotencyKey(req)
+	if r.compression && body != nil {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	res, err := r.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return r.readResponse(res)
+}
+
+// responseCount - безопасно достаёт числовое поле-счётчик из ответа сервера.
+// Поддерживаются как float64, так и json.Number (см. WithUseNumber).
+func responseCount(resp map[string]interface{}, key string) (int64, error) {
+	value, ok := resp[key]
+	if !ok {
+		return 0, fmt.Errorf("unexpected response shape: missing %s", key)
+	}
+
+	switch count := value.(type) {
+	case float64:
+		return int64(count), nil
+	case json.Number:
+		if n, err := count.Int64(); err == nil {
+			return n, nil
+		}
+		f, err := count.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("unexpected response shape: %s is not a number: %w", key, err)
+		}
+		return int64(f), nil
+	}
+	return 0, fmt.Errorf("unexpected response shape: %s is %T, not a number", key, value)
+}
+
+// responseDocument - достаёт документ из поля "value" ответа на операции вида findOneAndX
+func responseDocument(resp map[string]interface{}) (map[string]interface{}, error) {
+	value, ok := resp["value"]
+	if !ok || value == nil {
+		return nil, ErrNotFound
+	}
+	doc, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response shape: value is %T, not an object", value)
+	}
+	return doc, nil
+}
+
+// responseDocuments - достаёт массив документов из поля "data" ответа на чтение по фильтру
+func responseDocuments(resp map[string]interface{}) ([]map[string]interface{}, error) {
+	value, ok := resp["data"]
+	if !ok || value == nil {
+		return []map[string]interface{}{}, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response shape: data is %T, not an array", value)
+	}
+
+	docs := make([]map[string]interface{}, 0, len(items))
+	for i, item := range items {
+		doc, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected response shape: data[%d] is %T, not an object", i, item)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// SetTimeout - изменить таймаут клиента во время работы (0 - без таймаута).
+// Запросы, уже находящиеся в процессе, продолжают использовать прежний таймаут.
+func (r *Racs) SetTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.client = withClientTimeout(r.client, d)
+}
+
+// withClientTimeout - возвращает копию клиента с новым таймаутом, не изменяя исходный
+func withClientTimeout(c *http.Client, d time.Duration) *http.Client {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	clone := *c
+	clone.Timeout = d
+	return &clone
+}
+
+// SetHeader - потокобезопасно задать заголовок, отправляемый с каждым запросом
+func (r *Racs) SetHeader(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Headers == nil {
+		r.Headers = make(map[string]string)
+	}
+	r.Headers[key] = value
+}
+
+// DeleteHeader - потокобезопасно удалить заголовок экземпляра
+func (r *Racs) DeleteHeader(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.Headers, key)
+}
+
+// setHeaders - копирует заголовки экземпляра в запрос
+func (r *Racs) setHeaders(req *http.Request) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for key, value := range r.Headers {
+		req.Header.Set(key, value)
+	}
+	if r.compression {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	r.setContextHeaders(req)
+}
+
+// do - отправляет запрос с учётом политики повторов (см. WithRetry)
+func (r *Racs) do(req *http.Request) (res *http.Response, err error) {
+	if ok, err := r.dryRun(req); ok {
+		return nil, err
+	}
+
+	req, cancel := r.withDefaultTimeout(req)
+	if cancel != nil {
+		defer func() {
+			if res != nil {
+				res.Body = &cancelBody{ReadCloser: res.Body, cancel: cancel}
+			} else {
+				cancel()
+			}
+		}()
+	}
+
+	if r.breaker != nil {
+		if !r.breaker.allow() {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, opError(req.Context(), req.URL.String(), ErrCircuitOpen)
+		}
+		defer func() {
+			r.breaker.record(circuitResult(req, res, err))
+		}()
+	}
+
+	start := time.Now()
+	req, span := r.startSpan(req)
+	defer func() {
+		captureResponseHeader(req, res)
+		endSpan(span, res, err)
+		r.observeRequest(req, res, start)
+		if err != nil {
+			err = opError(req.Context(), req.URL.String(), err)
+		}
+	}()
+
+	attempts := 1
+	if r.canRetry(req) {
+		attempts = r.retryAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		res, err := r.send(req)
+		if attempt >= attempts || !r.shouldRetry(req, res, err) {
+			return res, err
+		}
+
+		delay := r.retryDelay(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+		}
+		// не ждём, если сервер просит слишком долгую паузу или дедлайн контекста наступит раньше
+		if delay > maxRetryDelay {
+			return res, err
+		}
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+			return res, err
+		}
+
+		if res != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send - отправляет запрос один раз и сообщает о результате в WithLogger
+func (r *Racs) send(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := r.roundTrip(req)
+	if r.logger != nil {
+		r.logger(newRequestInfo(req, res, err, time.Since(start)))
+	}
+	return res, err
+}
+
+// roundTrip - ожидает разрешения ограничителя частоты, применяет аутентификацию и выполняет запрос;
+// ошибки таймаута всегда оборачивают context.DeadlineExceeded
+func (r *Racs) roundTrip(req *http.Request) (*http.Response, error) {
+	if r.limiter != nil {
+		if err := r.limiter.Wait(req.Context()); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	if r.auth != nil {
+		if err := r.auth.Apply(req); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	if err := r.runRequestHooks(req); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	if r.clientTrace != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), r.clientTrace))
+	}
+
+	res, err := r.httpClient().Do(req)
+	if err != nil {
+		var netErr net.Error
+		if !errors.Is(err, context.DeadlineExceeded) && errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+		}
+		return nil, err
+	}
+
+	if r.compression {
+		decompressResponse(res)
+	}
+	return res, nil
+}
+
+// buildURL - собирает URL запроса к BaseURL: сегменты пути и значения query экранируются
+func (r *Racs) buildURL(segments ...string) string {
+	var b strings.Builder
+	b.WriteString(r.BaseURL)
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(segment))
+	}
+	b.WriteString("?resource=")
+	b.WriteString(url.QueryEscape(r.Resource))
+	b.WriteString("&dataset=")
+	b.WriteString(url.QueryEscape(r.Dataset))
+	if len(r.queryParams) > 0 {
+		b.WriteByte('&')
+		b.WriteString(r.queryParams.Encode())
+	}
+	return b.String()
+}
+
+// httpClient - возвращает клиент экземпляра или http.DefaultClient, если Racs создан без NewRacs
+func (r *Racs) httpClient() *http.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.client == nil {
+		return http.DefaultClient
+	}
+	return r.client
+}
+
+// Close - закрыть простаивающие соединения HTTP-клиента. Клиент общий для
+// экземпляров, полученных через Clone, но активные запросы не прерываются,
+// а новые откроют соединения заново. Повторный вызов безопасен.
+func (r *Racs) Close() error {
+	r.httpClient().CloseIdleConnections()
+	return nil
+}
+
+// decodeResponse - читает тело ответа и декодирует его в map
+func (r *Racs) decodeResponse(res *http.Response) (map[string]interface{}, error) {
+	data, err := r.readResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.decodeMap(data)
+	if err != nil {
+		return nil, responseError(res, err)
+	}
+
+	if err := r.runResponseHooks(res.Request.Context(), res.Request.Method, res.Request.URL.String(), resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// readResponse - читает тело ответа (не более WithMaxResponseSize байт)
+// и возвращает *StatusError для статусов >= 400
+func (r *Racs) readResponse(res *http.Response) ([]byte, error) {
+	limit := r.maxResponseSize
+	if limit <= 0 {
+		limit = defaultMaxResponseSize
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, responseError(res, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, responseError(res, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, limit))
+	}
+
+	if res.StatusCode >= 400 {
+		statusErr := &StatusError{
+			StatusCode: res.StatusCode,
+			Status:     res.Status,
+			Body:       data,
+		}
+		var result map[string]interface{}
+		if json.Unmarshal(data, &result) == nil {
+			statusErr.Response = result
+			statusErr.Server = parseServerError(result)
+		}
+		if res.StatusCode == http.StatusTooManyRequests {
+			retryAfter, _ := parseRetryAfter(res.Header.Get("Retry-After"))
+			return nil, responseError(res, &TooManyRequestsError{RetryAfter: retryAfter, Err: statusErr})
+		}
+		if dupErr := duplicateKeyError(statusErr); dupErr != nil {
+			return nil, responseError(res, dupErr)
+		}
+		return nil, responseError(res, statusErr)
+	}
+
+	return data, nil
 }
@@ -0,0 +1,110 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter - ограничитель частоты исходящих запросов.
+// Интерфейсу удовлетворяет *rate.Limiter из golang.org/x/time/rate.
+type RateLimiter interface {
+	// Wait - блокируется до получения разрешения на запрос или отмены ctx
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimit - ограничить частоту запросов значением rps в секунду с допустимым всплеском burst.
+// Каждая попытка отправки (включая повторы) ожидает свободный токен с учётом контекста запроса.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(r *Racs) error {
+		if rps <= 0 {
+			return errors.New("rate limit must be positive")
+		}
+		if burst < 1 {
+			return errors.New("rate limit burst must be at least 1")
+		}
+		r.limiter = newTokenBucket(rps, burst)
+		return nil
+	}
+}
+
+// WithRateLimiter - использовать собственный ограничитель частоты запросов, например
+// rate.NewLimiter(rate.Limit(rps), burst) из golang.org/x/time/rate
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(r *Racs) error {
+		if limiter == nil {
+			return errors.New("rate limiter can't be nil")
+		}
+		r.limiter = limiter
+		return nil
+	}
+}
+
+// tokenBucket - потокобезопасная реализация алгоритма token bucket с тем же поведением Wait,
+// что у rate.Limiter: токен берётся в долг, а при отмене ожидания возвращается. Собственная
+// реализация позволяет библиотеке обходиться без внешних зависимостей; rate.Limiter можно
+// подключить через WithRateLimiter.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	wait := b.reserve()
+	if wait <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		b.cancel()
+		return fmt.Errorf("rate limit wait %s exceeds context deadline: %w", wait, context.DeadlineExceeded)
+	}
+
+	if err := sleepContext(ctx, wait); err != nil {
+		b.cancel()
+		return err
+	}
+	return nil
+}
+
+// reserve - забирает токен (возможно, в долг) и возвращает время ожидания до его появления
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel - возвращает токен, взятый неудавшимся ожиданием
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+1)
+}
@@ -0,0 +1,81 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	b := newTokenBucket(1, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() #%d error = %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("burst of 3 took %s, want no waiting", elapsed)
+	}
+
+	if wait := b.reserve(); wait < 900*time.Millisecond {
+		t.Fatalf("wait after burst = %s, want about 1s", wait)
+	}
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	b := newTokenBucket(50, 1)
+
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond || elapsed > 200*time.Millisecond {
+		t.Fatalf("second Wait took %s, want about 20ms", elapsed)
+	}
+}
+
+func TestTokenBucketRefundOnCancel(t *testing.T) {
+	b := newTokenBucket(1, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := b.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v, want context.Canceled", err)
+	}
+
+	// без возврата токена следующее ожидание было бы около 2s
+	if wait := b.reserve(); wait > 1100*time.Millisecond {
+		t.Fatalf("wait after cancelled Wait = %s, want token refunded", wait)
+	}
+}
+
+func TestTokenBucketDeadline(t *testing.T) {
+	b := newTokenBucket(1, 1)
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := b.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Fatalf("Wait() took %s, want immediate failure", elapsed)
+	}
+	if wait := b.reserve(); wait > 1100*time.Millisecond {
+		t.Fatalf("wait after deadline failure = %s, want token refunded", wait)
+	}
+}
@@ -0,0 +1,232 @@
+package racs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// CreatePostResult - типизированный результат создания документа
+type CreatePostResult struct {
+	// InsertedID - ID созданного документа; пустая строка, если сервер его не вернул
+	InsertedID   string
+	Acknowledged bool
+}
+
+// CreatePostTyped - то же, что CreatePost, но возвращает типизированный результат
+func (r *Racs) CreatePostTyped(data map[string]interface{}) (*CreatePostResult, error) {
+	return r.CreatePostTypedContext(context.Background(), data)
+}
+
+func (r *Racs) CreatePostTypedContext(ctx context.Context, data map[string]interface{}) (*CreatePostResult, error) {
+	ctx = withOperation(ctx, "CreatePostTyped")
+
+	resp, err := r.CreatePostContext(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	acknowledged, _ := resp["acknowledged"].(bool)
+	return &CreatePostResult{
+		InsertedID:   idString(resp["insertedId"]),
+		Acknowledged: acknowledged,
+	}, nil
+}
+
+// CreatePostID - создать документ и вернуть только его ID. Если сервер не вернул ID
+// ни в "insertedId", ни в "_id", возвращается ошибка.
+func (r *Racs) CreatePostID(data map[string]interface{}) (string, error) {
+	return r.CreatePostIDContext(context.Background(), data)
+}
+
+func (r *Racs) CreatePostIDContext(ctx context.Context, data map[string]interface{}) (string, error) {
+	ctx = withOperation(ctx, "CreatePostID")
+
+	resp, err := r.CreatePostContext(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	id := idString(resp["insertedId"])
+	if id == "" {
+		id = idString(resp["_id"])
+	}
+	if id == "" {
+		return "", errors.New("unexpected response shape: missing insertedId")
+	}
+
+	return id, nil
+}
+
+// UpdateResult - типизированный результат обновления документов
+type UpdateResult struct {
+	MatchedCount  int64
+	ModifiedCount int64
+	// UpsertedID - ID документа, созданного при upsert; пустая строка, если документ не создавался
+	UpsertedID string
+	// NoOpUpdate - часть найденных документов не изменилась (MatchedCount > ModifiedCount)
+	NoOpUpdate bool
+}
+
+// UpdatePostByIDTyped - то же, что UpdatePostByID, но возвращает типизированный результат
+func (r *Racs) UpdatePostByIDTyped(postID string, updateOptions map[string]interface{}) (*UpdateResult, error) {
+	return r.UpdatePostByIDTypedContext(context.Background(), postID, updateOptions)
+}
+
+func (r *Racs) UpdatePostByIDTypedContext(ctx context.Context, postID string, updateOptions map[string]interface{}) (*UpdateResult, error) {
+	ctx = withOperation(ctx, "UpdatePostByIDTyped")
+
+	resp, err := r.UpdatePostByIDContext(ctx, postID, updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUpdateResult(resp)
+}
+
+// UpdatePostByFilterTyped - то же, что UpdatePostByFilter, но возвращает типизированный результат
+func (r *Racs) UpdatePostByFilterTyped(filterData, updateOptions map[string]interface{}) (*UpdateResult, error) {
+	return r.UpdatePostByFilterTypedContext(context.Background(), filterData, updateOptions)
+}
+
+func (r *Racs) UpdatePostByFilterTypedContext(ctx context.Context, filterData, updateOptions map[string]interface{}) (*UpdateResult, error) {
+	ctx = withOperation(ctx, "UpdatePostByFilterTyped")
+
+	resp, err := r.UpdatePostByFilterContext(ctx, filterData, updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUpdateResult(resp)
+}
+
+// UpdatePostByFilterN - то же, что UpdatePostByFilter, но возвращает только счётчики
+// найденных и изменённых документов
+func (r *Racs) UpdatePostByFilterN(filterData, updateOptions map[string]interface{}) (int64, int64, error) {
+	return r.UpdatePostByFilterNContext(context.Background(), filterData, updateOptions)
+}
+
+func (r *Racs) UpdatePostByFilterNContext(ctx context.Context, filterData, updateOptions map[string]interface{}) (int64, int64, error) {
+	ctx = withOperation(ctx, "UpdatePostByFilterN")
+
+	result, err := r.UpdatePostByFilterTypedContext(ctx, filterData, updateOptions)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return result.MatchedCount, result.ModifiedCount, nil
+}
+
+// UpsertPostByFilterTyped - то же, что UpsertPostByFilter, но возвращает типизированный результат
+func (r *Racs) UpsertPostByFilterTyped(filterData, updateOptions map[string]interface{}) (*UpdateResult, error) {
+	return r.UpsertPostByFilterTypedContext(context.Background(), filterData, updateOptions)
+}
+
+func (r *Racs) UpsertPostByFilterTypedContext(ctx context.Context, filterData, updateOptions map[string]interface{}) (*UpdateResult, error) {
+	ctx = withOperation(ctx, "UpsertPostByFilterTyped")
+
+	resp, err := r.UpsertPostByFilterContext(ctx, filterData, updateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUpdateResult(resp)
+}
+
+// DeleteResult - типизированный результат удаления документов
+type DeleteResult struct {
+	// DeletedCount - число удалённых документов; -1, если сервер ответил пустым телом
+	// (например, 204 No Content) и не сообщил количество
+	DeletedCount int64
+}
+
+// DeletePostByIDTyped - то же, что DeletePostByID, но возвращает типизированный результат
+func (r *Racs) DeletePostByIDTyped(postID string) (*DeleteResult, error) {
+	return r.DeletePostByIDTypedContext(context.Background(), postID)
+}
+
+func (r *Racs) DeletePostByIDTypedContext(ctx context.Context, postID string) (*DeleteResult, error) {
+	ctx = withOperation(ctx, "DeletePostByIDTyped")
+
+	resp, err := r.DeletePostByIDContext(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDeleteResult(resp)
+}
+
+// DeletePostByFilterTyped - то же, что DeletePostByFilter, но возвращает типизированный результат
+func (r *Racs) DeletePostByFilterTyped(filterData map[string]interface{}) (*DeleteResult, error) {
+	return r.DeletePostByFilterTypedContext(context.Background(), filterData)
+}
+
+func (r *Racs) DeletePostByFilterTypedContext(ctx context.Context, filterData map[string]interface{}) (*DeleteResult, error) {
+	ctx = withOperation(ctx, "DeletePostByFilterTyped")
+
+	resp, err := r.DeletePostByFilterContext(ctx, filterData)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDeleteResult(resp)
+}
+
+// newDeleteResult - разбирает счётчик ответа на удаление
+func newDeleteResult(resp map[string]interface{}) (*DeleteResult, error) {
+	if len(resp) == 0 {
+		return &DeleteResult{DeletedCount: -1}, nil
+	}
+
+	deleted, err := responseCount(resp, "deletedCount")
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeleteResult{DeletedCount: deleted}, nil
+}
+
+// newUpdateResult - разбирает счётчики ответа на обновление. При upsert сервер
+// может не вернуть счётчики, тогда они считаются нулевыми.
+func newUpdateResult(resp map[string]interface{}) (*UpdateResult, error) {
+	result := &UpdateResult{UpsertedID: idString(resp["upsertedId"])}
+
+	for key, count := range map[string]*int64{
+		"matchedCount":  &result.MatchedCount,
+		"modifiedCount": &result.ModifiedCount,
+	} {
+		if _, ok := resp[key]; !ok && result.UpsertedID != "" {
+			continue
+		}
+		n, err := responseCount(resp, key)
+		if err != nil {
+			return nil, err
+		}
+		*count = n
+	}
+	result.NoOpUpdate = result.MatchedCount > result.ModifiedCount
+
+	return result, nil
+}
+
+// idString - приводит идентификатор из ответа к строке.
+// Поддерживаются строки, числа и расширенный JSON вида {"$oid": "..."}.
+func idString(value interface{}) string {
+	switch id := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	case json.Number:
+		return id.String()
+	case map[string]interface{}:
+		if oid, ok := id["$oid"].(string); ok {
+			return oid
+		}
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,141 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryDelay - верхняя граница задержки между повторами
+const maxRetryDelay = 30 * time.Second
+
+// WithRetry - повторять идемпотентные запросы (GET, HEAD) при сетевых ошибках и
+// статусах 502/503/504. Задержка растёт экспоненциально от baseDelay со случайным разбросом.
+// Ответ 429 повторяется для любого метода, так как сервер его не обработал; при наличии
+// заголовка Retry-After ожидание равно указанному в нём времени.
+// maxAttempts - общее число попыток, включая первую. Остальные ответы 4xx не повторяются.
+// Ожидание между попытками прерывается при отмене контекста запроса.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(r *Racs) error {
+		if maxAttempts < 1 {
+			return errors.New("retry attempts must be at least 1")
+		}
+		if baseDelay <= 0 {
+			return errors.New("retry delay must be positive")
+		}
+		r.retryAttempts = maxAttempts
+		r.retryBaseDelay = baseDelay
+		return nil
+	}
+}
+
+// WithRetryOnDelete - дополнительно повторять DELETE-запросы (используется вместе с WithRetry)
+func WithRetryOnDelete() Option {
+	return func(r *Racs) error {
+		r.retryDelete = true
+		return nil
+	}
+}
+
+// canRetry - включены ли повторы и можно ли повторно отправить тело запроса
+func (r *Racs) canRetry(req *http.Request) bool {
+	if r.retryAttempts <= 1 {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// shouldRetry - является ли результат попытки временной ошибкой, после которой запрос можно повторить
+func (r *Racs) shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err == nil && res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !r.idempotent(req.Method) && req.Header.Get(idempotencyKeyHeader) == "" {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// idempotent - повторяются ли сбои запросов с этим методом
+func (r *Racs) idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodDelete:
+		return r.retryDelete
+	}
+	return false
+}
+
+// parseRetryAfter - разбирает заголовок Retry-After в формате секунд или HTTP-даты
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// retryDelay - экспоненциальная задержка перед попыткой attempt+1 с разбросом в половину интервала
+func (r *Racs) retryDelay(attempt int) time.Duration {
+	delay := r.retryBaseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// sleepContext - ждёт d или отмены контекста
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewindRequest - готовит копию запроса для повторной отправки с новым телом
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		next.Body = body
+	}
+	return next, nil
+}
@@ -0,0 +1,45 @@
+package racs
+
+import (
+	"context"
+	"errors"
+)
+
+// Search - полнотекстовый поиск ($text) с дополнительным фильтром filterData (может быть nil).
+// Документы возвращаются по убыванию релевантности; её оценка записывается в поле "score".
+// Для поиска в коллекции должен существовать текстовый индекс.
+func (r *Racs) Search(text string, filterData map[string]interface{}, limit int) ([]map[string]interface{}, error) {
+	return r.SearchContext(context.Background(), text, filterData, limit)
+}
+
+func (r *Racs) SearchContext(ctx context.Context, text string, filterData map[string]interface{}, limit int) ([]map[string]interface{}, error) {
+	ctx = withOperation(ctx, "Search")
+
+	if text == "" {
+		return nil, errors.New(`"text" is required`)
+	}
+	if _, ok := filterData["$text"]; ok {
+		return nil, errors.New(`"filter_data" can't contain $text`)
+	}
+
+	filter := make(map[string]interface{}, len(filterData)+1)
+	for key, value := range filterData {
+		filter[key] = value
+	}
+	filter["$text"] = map[string]interface{}{"$search": text}
+
+	score := map[string]interface{}{
+		"score": map[string]interface{}{"$meta": "textScore"},
+	}
+	resp, err := r.readByFilter(ctx, readQuery{
+		filter:     filter,
+		sort:       score,
+		limit:      limit,
+		projection: score,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return responseDocuments(resp)
+}
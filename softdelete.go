@@ -0,0 +1,66 @@
+package racs
+
+import (
+	"context"
+	"time"
+)
+
+// softDeleteField - поле с моментом мягкого удаления документа
+const softDeleteField = "deletedAt"
+
+// SoftDeletePostByID - пометить документ удалённым, записав текущее время (UTC) в поле "deletedAt",
+// вместо физического удаления. Для физического удаления используйте DeletePostByID.
+func (r *Racs) SoftDeletePostByID(postID string) (map[string]interface{}, error) {
+	return r.SoftDeletePostByIDContext(context.Background(), postID)
+}
+
+func (r *Racs) SoftDeletePostByIDContext(ctx context.Context, postID string) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "SoftDeletePostByID")
+
+	return r.UpdatePostByIDContext(ctx, postID, map[string]interface{}{
+		softDeleteField: time.Now().UTC(),
+	})
+}
+
+// WithSoftDeleteFilter - исключать мягко удалённые документы (с полем "deletedAt") из чтения:
+// к фильтрам ReadPostByFilter, FindOne, Paginate, Find, Search, PeekByFilter и Distinct добавляется
+// {"deletedAt": {"$exists": false}}, а ReadPostByID для такого документа возвращает ErrNotFound.
+// Exists и методы для файлов опцию не учитывают.
+func WithSoftDeleteFilter() Option {
+	return func(r *Racs) error {
+		r.softDeleteFilter = true
+		return nil
+	}
+}
+
+// liveFilter - добавляет к фильтру условие отсутствия "deletedAt", если включён WithSoftDeleteFilter.
+// Фильтр, уже содержащий условие на "deletedAt", не изменяется; исходная map не модифицируется.
+func (r *Racs) liveFilter(filterData interface{}) interface{} {
+	if !r.softDeleteFilter {
+		return filterData
+	}
+
+	notDeleted := map[string]interface{}{
+		softDeleteField: map[string]interface{}{"$exists": false},
+	}
+	switch filter := filterData.(type) {
+	case nil:
+		return notDeleted
+	case map[string]interface{}:
+		if _, ok := filter[softDeleteField]; ok {
+			return filter
+		}
+		merged := make(map[string]interface{}, len(filter)+1)
+		for key, value := range filter {
+			merged[key] = value
+		}
+		merged[softDeleteField] = notDeleted[softDeleteField]
+		return merged
+	}
+	return map[string]interface{}{"$and": []interface{}{filterData, notDeleted}}
+}
+
+// softDeleted - true, если включён WithSoftDeleteFilter и документ помечен удалённым
+func (r *Racs) softDeleted(doc map[string]interface{}) bool {
+	return r.softDeleteFilter && doc[softDeleteField] != nil
+}
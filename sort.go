@@ -0,0 +1,66 @@
+package racs
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SortBuilder - построитель сортировки, сохраняющий порядок полей при сериализации.
+// Передаётся в параметр sort методов чтения по фильтру.
+type SortBuilder struct {
+	fields     []string
+	directions []int
+}
+
+// Sort - создаёт пустой построитель сортировки.
+// Пример: Sort().Desc("priority").Asc("_created")
+func Sort() *SortBuilder {
+	return &SortBuilder{}
+}
+
+// Asc - сортировка по возрастанию поля
+func (s *SortBuilder) Asc(field string) *SortBuilder {
+	return s.add(field, 1)
+}
+
+// Desc - сортировка по убыванию поля
+func (s *SortBuilder) Desc(field string) *SortBuilder {
+	return s.add(field, -1)
+}
+
+// MarshalJSON - сериализует сортировку в JSON-объект с полями в порядке добавления
+func (s *SortBuilder) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, field := range s.fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		if s.directions[i] < 0 {
+			b.WriteString("-1")
+		} else {
+			b.WriteString("1")
+		}
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// add - добавляет поле; повторное добавление меняет направление, не меняя позицию поля
+func (s *SortBuilder) add(field string, direction int) *SortBuilder {
+	for i, existing := range s.fields {
+		if existing == field {
+			s.directions[i] = direction
+			return s
+		}
+	}
+	s.fields = append(s.fields, field)
+	s.directions = append(s.directions, direction)
+	return s
+}
@@ -0,0 +1,147 @@
+package racs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StreamPostsByFilter - читает документы по фильтру и декодирует массив "data" по одному
+// элементу, вызывая fn для каждого документа по мере разбора ответа. Память не растёт
+// с размером выборки, поэтому WithMaxResponseSize к ответу не применяется. Обход
+// останавливается на первой ошибке fn. Документы всегда декодируются encoding/json
+// (с учётом WithUseNumber), даже если задан WithJSONCodec.
+func (r *Racs) StreamPostsByFilter(filterData interface{}, sort interface{}, limit int, fn func(doc map[string]interface{}) error) error {
+	return r.StreamPostsByFilterContext(context.Background(), filterData, sort, limit, fn)
+}
+
+func (r *Racs) StreamPostsByFilterContext(ctx context.Context, filterData interface{}, sort interface{}, limit int, fn func(doc map[string]interface{}) error) error {
+	ctx = withOperation(ctx, "StreamPostsByFilter")
+
+	return StreamPostsByFilterIntoContext(ctx, r, filterData, sort, limit, fn)
+}
+
+// StreamPostsByFilterInto - то же, что StreamPostsByFilter, но декодирует документы в значения типа T
+func StreamPostsByFilterInto[T any](r *Racs, filterData interface{}, sort interface{}, limit int, fn func(doc T) error) error {
+	return StreamPostsByFilterIntoContext(context.Background(), r, filterData, sort, limit, fn)
+}
+
+func StreamPostsByFilterIntoContext[T any](ctx context.Context, r *Racs, filterData interface{}, sort interface{}, limit int, fn func(doc T) error) error {
+	ctx = withOperation(ctx, "StreamPostsByFilterInto")
+
+	if fn == nil {
+		return errors.New(`"fn" is required`)
+	}
+
+	req, err := r.streamRequest(ctx, readQuery{filter: filterData, sort: sort, limit: limit})
+	if err != nil {
+		return err
+	}
+
+	res, err := r.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 {
+		_, err := r.readResponse(res)
+		return err
+	}
+
+	decoder := json.NewDecoder(res.Body)
+	if r.useNumber {
+		decoder.UseNumber()
+	}
+
+	var fnErr error
+	err = streamData(decoder, func() error {
+		var doc T
+		if err := decoder.Decode(&doc); err != nil {
+			return err
+		}
+		fnErr = fn(doc)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return responseError(res, err)
+	}
+
+	return nil
+}
+
+// streamRequest - запрос на чтение по фильтру, тело ответа которого читается потоково
+func (r *Racs) streamRequest(ctx context.Context, q readQuery) (*http.Request, error) {
+	payload, err := r.marshal(r.readRequest(q))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", r.buildURL("get"), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	r.setHeaders(req)
+	return req, nil
+}
+
+// streamData - проходит по объекту ответа и для каждого элемента массива "data" вызывает next,
+// который должен прочитать ровно одно значение из decoder; остальные поля пропускаются
+func streamData(decoder *json.Decoder, next func() error) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if key, _ := token.(string); key != "data" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+
+		token, err = decoder.Token()
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			continue
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return fmt.Errorf("unexpected response shape: data is %v, not an array", token)
+		}
+		for decoder.More() {
+			if err := next(); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+// expectDelim - читает следующий токен и проверяет, что это ожидаемый разделитель
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %q, got %v", want, token)
+	}
+	return nil
+}
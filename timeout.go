@@ -0,0 +1,54 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithDefaultTimeout - дедлайн для вызовов, контекст которых его не содержит
+// (в том числе всех методов без суффикса Context). Дедлайн, заданный вызывающим,
+// всегда имеет приоритет, так что для отдельной медленной операции можно передать
+// как более короткий, так и более длинный срок.
+//
+// В отличие от WithTimeout (http.Client.Timeout), который ограничивает каждую попытку
+// и действует независимо от контекста, дедлайн контекста ограничивает вызов целиком,
+// включая повторы и ожидание WithRateLimit. Если заданы оба, запрос прерывается
+// по тому, что наступит раньше.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(r *Racs) error {
+		if d <= 0 {
+			return errors.New("default timeout must be positive")
+		}
+		r.defaultTimeout = d
+		return nil
+	}
+}
+
+// withDefaultTimeout - добавляет к запросу дедлайн из WithDefaultTimeout, если его нет в контексте.
+// Возвращаемая функция отменяет контекст; после успешного ответа это делает закрытие тела.
+func (r *Racs) withDefaultTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	if r.defaultTimeout <= 0 {
+		return req, nil
+	}
+	if _, ok := req.Context().Deadline(); ok {
+		return req, nil
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), r.defaultTimeout)
+	return req.WithContext(ctx), cancel
+}
+
+// cancelBody - тело ответа, отменяющее контекст запроса при закрытии
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
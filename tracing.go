@@ -0,0 +1,107 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+)
+
+// TracerProvider - источник Tracer для WithTracerProvider.
+// Интерфейсы трассировки намеренно минимальны, чтобы библиотека не зависела от OpenTelemetry;
+// адаптер к go.opentelemetry.io/otel/trace занимает несколько строк.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer - создаёт span'ы, дочерние по отношению к span'у из ctx
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span - активный span операции
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// tracerName - имя инструментирующей библиотеки, передаваемое в TracerProvider
+const tracerName = "github.com/miilkaa/racs-go-lib"
+
+// WithTracerProvider - создавать span "racs.<Операция>" для каждого запроса.
+// Span включает все повторные попытки и содержит атрибуты racs.resource, racs.dataset,
+// http.method и http.status_code.
+func WithTracerProvider(tp TracerProvider) Option {
+	return func(r *Racs) error {
+		if tp == nil {
+			return errors.New("tracer provider can't be nil")
+		}
+		r.tracer = tp.Tracer(tracerName)
+		return nil
+	}
+}
+
+// WithClientTrace - подключить httptrace.ClientTrace ко всем запросам экземпляра,
+// например чтобы по GotConnInfo.Reused проверить переиспользование соединений.
+// Трассировку для отдельного вызова можно передать через контекст методов *Context
+// (httptrace.WithClientTrace); обе трассировки срабатывают вместе.
+func WithClientTrace(trace *httptrace.ClientTrace) Option {
+	return func(r *Racs) error {
+		if trace == nil {
+			return errors.New("client trace can't be nil")
+		}
+		r.clientTrace = trace
+		return nil
+	}
+}
+
+// operationKey - ключ контекста с именем публичного метода, выполняющего запрос
+type operationKey struct{}
+
+// withOperation - запоминает имя операции в контексте; имя внешнего метода не перезаписывается
+func withOperation(ctx context.Context, op string) context.Context {
+	if _, ok := ctx.Value(operationKey{}).(string); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// operationFrom - имя операции из контекста или "request", если оно не задано
+func operationFrom(ctx context.Context) string {
+	if op, ok := ctx.Value(operationKey{}).(string); ok {
+		return op
+	}
+	return "request"
+}
+
+// startSpan - начинает span для запроса, если задан WithTracerProvider
+func (r *Racs) startSpan(req *http.Request) (*http.Request, Span) {
+	if r.tracer == nil {
+		return req, nil
+	}
+
+	ctx, span := r.tracer.Start(req.Context(), "racs."+operationFrom(req.Context()))
+	span.SetAttribute("racs.resource", r.Resource)
+	span.SetAttribute("racs.dataset", r.Dataset)
+	span.SetAttribute("http.method", req.Method)
+	return req.WithContext(ctx), span
+}
+
+// endSpan - записывает результат запроса в span и завершает его
+func endSpan(span Span, res *http.Response, err error) {
+	if span == nil {
+		return
+	}
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+
+	span.SetAttribute("http.status_code", res.StatusCode)
+	if res.StatusCode >= 400 {
+		span.RecordError(&StatusError{StatusCode: res.StatusCode, Status: res.Status})
+	}
+}
@@ -0,0 +1,117 @@
+package racs
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// transportSettings - параметры пула соединений для транспорта по умолчанию
+type transportSettings struct {
+	configured bool
+
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+
+	tlsConfig          *tls.Config
+	insecureSkipVerify bool
+}
+
+// WithMaxIdleConns - ограничение общего числа простаивающих соединений (http.Transport.MaxIdleConns).
+// Игнорируется, если клиент задан через WithHTTPClient.
+func WithMaxIdleConns(n int) Option {
+	return func(r *Racs) error {
+		if n < 0 {
+			return errors.New("max idle conns can't be negative")
+		}
+		r.transport.maxIdleConns = n
+		r.transport.configured = true
+		return nil
+	}
+}
+
+// WithMaxIdleConnsPerHost - число простаивающих соединений на хост (http.Transport.MaxIdleConnsPerHost,
+// по умолчанию 2). Увеличение помогает при большом числе параллельных запросов.
+// Игнорируется, если клиент задан через WithHTTPClient.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(r *Racs) error {
+		if n < 0 {
+			return errors.New("max idle conns per host can't be negative")
+		}
+		r.transport.maxIdleConnsPerHost = n
+		r.transport.configured = true
+		return nil
+	}
+}
+
+// WithIdleConnTimeout - время жизни простаивающего соединения (http.Transport.IdleConnTimeout).
+// Игнорируется, если клиент задан через WithHTTPClient.
+func WithIdleConnTimeout(d time.Duration) Option {
+	return func(r *Racs) error {
+		if d < 0 {
+			return errors.New("idle conn timeout can't be negative")
+		}
+		r.transport.idleConnTimeout = d
+		r.transport.configured = true
+		return nil
+	}
+}
+
+// WithTLSConfig - использовать tlsConfig в транспорте по умолчанию, например для клиентских
+// сертификатов (mTLS) и собственного пула корневых сертификатов. Сочетается с опциями пула
+// соединений и WithInsecureSkipVerify. Игнорируется, если клиент задан через WithHTTPClient.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(r *Racs) error {
+		if tlsConfig == nil {
+			return errors.New("tls config can't be nil")
+		}
+		r.transport.tlsConfig = tlsConfig
+		r.transport.configured = true
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify - отключить проверку TLS-сертификата сервера.
+//
+// ТОЛЬКО ДЛЯ ЛОКАЛЬНОЙ РАЗРАБОТКИ (например, self-hosted racs с самоподписанным
+// сертификатом): соединение становится уязвимым для перехвата. При создании экземпляра
+// выводится предупреждение (см. WithWarningHandler). Игнорируется, если клиент задан
+// через WithHTTPClient.
+func WithInsecureSkipVerify() Option {
+	return func(r *Racs) error {
+		r.transport.insecureSkipVerify = true
+		r.transport.configured = true
+		return nil
+	}
+}
+
+// applyTransport - собрать http.Transport по опциям пула, если клиент не был передан извне
+func (r *Racs) applyTransport() {
+	if !r.transport.configured || r.customClient {
+		return
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if r.transport.maxIdleConns > 0 {
+		t.MaxIdleConns = r.transport.maxIdleConns
+	}
+	if r.transport.maxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = r.transport.maxIdleConnsPerHost
+	}
+	if r.transport.idleConnTimeout > 0 {
+		t.IdleConnTimeout = r.transport.idleConnTimeout
+	}
+	if r.transport.tlsConfig != nil {
+		t.TLSClientConfig = r.transport.tlsConfig.Clone()
+	}
+	if r.transport.insecureSkipVerify {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+		r.warn("TLS certificate verification is disabled (WithInsecureSkipVerify). Do not use it in production.")
+	}
+	r.client.Transport = t
+}
@@ -0,0 +1,172 @@
+package racs
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Validate - проверить, что данные можно закодировать в JSON, до отправки запроса.
+// Ошибка оборачивает ErrInvalidData и содержит путь к проблемному полю,
+// например `items[2].callback`.
+func Validate(data interface{}) error {
+	return validateValue("", reflect.ValueOf(data), make(map[visit]struct{}))
+}
+
+// WithRequiredFields - проверять перед CreatePost и CreatePosts, что в документе есть
+// все перечисленные поля верхнего уровня; иначе возвращается ошибка, оборачивающая
+// ErrInvalidData, со списком отсутствующих полей, и запрос не отправляется
+func WithRequiredFields(fields ...string) Option {
+	return func(r *Racs) error {
+		for _, field := range fields {
+			if field == "" {
+				return errors.New("required field name can't be empty")
+			}
+		}
+		r.requiredFields = append(r.requiredFields[:len(r.requiredFields):len(r.requiredFields)], fields...)
+		return nil
+	}
+}
+
+// checkRequired - проверяет наличие полей из WithRequiredFields
+func (r *Racs) checkRequired(data map[string]interface{}) error {
+	var missing []string
+	for _, field := range r.requiredFields {
+		if _, ok := data[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing required fields: %s", ErrInvalidData, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// visit - map, срез или указатель на текущем пути обхода; срезы различаются ещё и длиной,
+// как в encoding/json, чтобы подсрез того же массива не считался циклом
+type visit struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+func validateValue(path string, v reflect.Value, seen map[visit]struct{}) error {
+	if !v.IsValid() {
+		return nil
+	}
+	// собственная сериализация типа проверяется только при кодировании
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		key := visit{typ: v.Type(), ptr: v.Pointer()}
+		if v.Kind() == reflect.Slice {
+			key.len = v.Len()
+		}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("%w: cycle at %s", ErrInvalidData, path)
+		}
+		seen[key] = struct{}{}
+		defer delete(seen, key)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return validateValue(path, v.Elem(), seen)
+
+	case reflect.Map:
+		switch v.Type().Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !v.Type().Key().Implements(textMarshalerType) {
+				return invalidField(path, "unsupported map key type "+v.Type().Key().String())
+			}
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			if err := validateValue(joinPath(path, fmt.Sprint(key.Interface())), v.MapIndex(key), seen); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := validateValue(path+"["+strconv.Itoa(i)+"]", v.Index(i), seen); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				if tag == "-" {
+					continue
+				}
+				if n, _, _ := strings.Cut(tag, ","); n != "" {
+					name = n
+				}
+			}
+			if err := validateValue(joinPath(path, name), v.Field(i), seen); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+			return invalidField(path, "unsupported value "+strconv.FormatFloat(f, 'g', -1, 64))
+		}
+
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return invalidField(path, "unsupported type "+v.Type().String())
+	}
+
+	return nil
+}
+
+func invalidField(path, reason string) error {
+	if path == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidData, reason)
+	}
+	return fmt.Errorf("%w: field %q: %s", ErrInvalidData, path, reason)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,59 @@
+package racs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateCycle(t *testing.T) {
+	m := map[string]interface{}{"name": "x"}
+	m["self"] = m
+
+	err := Validate(m)
+	if !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("Validate() error = %v, want ErrInvalidData", err)
+	}
+	if !strings.Contains(err.Error(), "cycle at self") {
+		t.Fatalf("Validate() error = %q, want cycle path", err)
+	}
+}
+
+func TestValidateCyclePointer(t *testing.T) {
+	type node struct {
+		Next *node `json:"next"`
+	}
+	n := &node{}
+	n.Next = n
+
+	err := Validate(n)
+	if !errors.Is(err, ErrInvalidData) || !strings.Contains(err.Error(), "cycle at next") {
+		t.Fatalf("Validate() error = %v, want cycle at next", err)
+	}
+}
+
+func TestValidateSharedValueIsNotCycle(t *testing.T) {
+	shared := map[string]interface{}{"a": 1}
+	data := map[string]interface{}{
+		"first":  shared,
+		"second": shared,
+		"list":   []interface{}{shared, shared},
+	}
+
+	if err := Validate(data); err != nil {
+		t.Fatalf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestCreatePostCycle(t *testing.T) {
+	r, err := NewRacs("resource", "dataset", WithBaseURL("http://127.0.0.1:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]interface{}{}
+	m["self"] = []interface{}{m}
+
+	if _, err := r.CreatePost(m); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("CreatePost() error = %v, want ErrInvalidData", err)
+	}
+}
@@ -0,0 +1,7 @@
+package racs
+
+// Version - версия библиотеки, передаваемая в User-Agent по умолчанию
+const Version = "0.1.0"
+
+// defaultUserAgent - значение заголовка User-Agent по умолчанию
+const defaultUserAgent = "racs-go-lib/" + Version
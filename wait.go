@@ -0,0 +1,36 @@
+package racs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// WaitForPost - опрашивать сервер через FindOne с интервалом interval, пока не появится
+// документ, подходящий под фильтр, или не завершится ctx. Используется, когда документ
+// создаётся асинхронно. Ошибки, кроме ErrNotFound, прерывают ожидание; при отмене
+// контекста возвращается ctx.Err().
+func (r *Racs) WaitForPost(ctx context.Context, filterData map[string]interface{}, interval time.Duration) (map[string]interface{}, error) {
+	ctx = withOperation(ctx, "WaitForPost")
+
+	if interval <= 0 {
+		return nil, errors.New(`"interval" must be positive`)
+	}
+
+	for {
+		doc, err := r.FindOneContext(ctx, filterData, nil)
+		if err == nil {
+			return doc, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
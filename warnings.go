@@ -0,0 +1,48 @@
+package racs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithWarningHandler - передавать предупреждения библиотеки (например, о том, что найденный
+// документ не был изменён обновлением) в fn вместо вывода в stdout
+func WithWarningHandler(fn func(message string)) Option {
+	return func(r *Racs) error {
+		if fn == nil {
+			return errors.New("warning handler can't be nil")
+		}
+		r.warnings = fn
+		return nil
+	}
+}
+
+// WithSilentWarnings - не выводить предупреждения библиотеки
+func WithSilentWarnings() Option {
+	return func(r *Racs) error {
+		r.silent = true
+		return nil
+	}
+}
+
+// WithNoEffectiveChangeError - возвращать ErrNoEffectiveChange вместо предупреждения,
+// если обновление нашло документы, но не изменило ни одного из них (modifiedCount = 0).
+// Частичное изменение (0 < modifiedCount < matchedCount) по-прежнему считается успехом
+// с предупреждением, и ответ со счётчиками возвращается.
+func WithNoEffectiveChangeError() Option {
+	return func(r *Racs) error {
+		r.noEffectiveChangeError = true
+		return nil
+	}
+}
+
+// warn - сообщает о предупреждении согласно настройкам экземпляра
+func (r *Racs) warn(message string) {
+	switch {
+	case r.silent:
+	case r.warnings != nil:
+		r.warnings(message)
+	default:
+		fmt.Println("Warning: " + message)
+	}
+}